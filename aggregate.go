@@ -0,0 +1,65 @@
+package dbmongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (db *Database) Aggregate(ctx context.Context, pipeline any, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return db.Database.Aggregate(ctx, pipeline, db.aggregateOptions(opts)...)
+}
+
+func (db *Database) AggregateDocuments(ctx context.Context, docs []bson.M, pipeline any) (*mongo.Cursor, error) {
+	if docs == nil {
+		docs = []bson.M{}
+	}
+	stages := bson.A{bson.D{{Key: "$documents", Value: docs}}}
+
+	if pipeline != nil {
+		t, data, err := bson.MarshalValue(pipeline)
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := bson.RawValue{Type: t, Value: data}.ArrayOK()
+		if !ok {
+			return nil, fmt.Errorf("pipeline must be an array of stages, not %s", t)
+		}
+		values, err := arr.Values()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			stages = append(stages, v)
+		}
+	}
+
+	return db.Aggregate(ctx, stages)
+}
+
+func (db *Database) ValidatePipeline(ctx context.Context, collection string, pipeline any) error {
+	return db.Database.RunCommand(ctx, bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "aggregate", Value: collection},
+			{Key: "pipeline", Value: pipeline},
+			{Key: "cursor", Value: bson.D{}},
+		}},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}).Err()
+}
+
+// aggregateOptions applies the channel AllowDiskUse default unless one of opts sets it explicitly.
+func (db *Database) aggregateOptions(opts []*options.AggregateOptions) []*options.AggregateOptions {
+	if !db.cfg.AllowDiskUse {
+		return opts
+	}
+	for _, o := range opts {
+		if o != nil && o.AllowDiskUse != nil {
+			return opts
+		}
+	}
+	return append(opts[:len(opts):len(opts)], options.Aggregate().SetAllowDiskUse(true))
+}
@@ -0,0 +1,51 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAggregateDocumentsRejectsNonArrayPipeline(t *testing.T) {
+	db := lazyDatabase(t)
+
+	if _, err := db.AggregateDocuments(context.Background(), nil, bson.D{{Key: "$match", Value: bson.D{}}}); err == nil {
+		t.Error("document pipeline accepted")
+	}
+}
+
+func TestAggregateDocuments(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	docs := []bson.M{{"n": 1}, {"n": 2}, {"n": 3}}
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "n", Value: bson.D{{Key: "$gte", Value: 2}}}}}},
+		bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}, {Key: "double", Value: bson.D{{Key: "$multiply", Value: bson.A{"$n", 2}}}}}}},
+	}
+
+	cursor, err := db.AggregateDocuments(ctx, docs, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []struct {
+		Double int `bson:"double"`
+	}
+	if err = cursor.All(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Double != 4 || got[1].Double != 6 {
+		t.Errorf("aggregated %+v, want doubles 4 and 6", got)
+	}
+
+	cursor, err = db.AggregateDocuments(ctx, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Next(ctx) {
+		t.Errorf("no documents aggregated %v", cursor.Current)
+	}
+	_ = cursor.Close(ctx)
+}
@@ -0,0 +1,143 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrNotAuthenticated = errors.New("mongodb connection is not authenticated")
+	ErrNotAuthorized    = errors.New("mongodb user lacks the expected roles")
+)
+
+// authMechanisms are the authentication mechanisms accepted in AuthConfig.
+var authMechanisms = []string{"SCRAM-SHA-1", "SCRAM-SHA-256", "MONGODB-X509", "MONGODB-AWS", "PLAIN"}
+
+// AuthConfig sets the credentials of the channel as an alternative to embedding them in the DSN. The credential
+// fields set in the DSN (the user info, authMechanism, authMechanismProperties and authSource) take precedence one by
+// one, the others are kept from this section.
+type AuthConfig struct {
+	// AuthMechanism is one of SCRAM-SHA-1, SCRAM-SHA-256, MONGODB-X509, MONGODB-AWS or PLAIN. Empty lets the driver
+	// negotiate a SCRAM mechanism with the server.
+	AuthMechanism string `mapstructure:"auth_mechanism" json:"auth_mechanism,omitempty" yaml:"auth_mechanism,omitempty"`
+	AuthSource    string `mapstructure:"auth_source" json:"auth_source,omitempty" yaml:"auth_source,omitempty"`
+	Username      string `mapstructure:"username" json:"username,omitempty" yaml:"username,omitempty"`
+	Password      string `mapstructure:"password" json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// credential builds the driver credential, or returns nil when neither a mechanism nor a username is configured.
+func (c AuthConfig) credential() (*options.Credential, error) {
+	if c.AuthMechanism != "" && !slices.Contains(authMechanisms, c.AuthMechanism) {
+		return nil, fmt.Errorf("%w: unsupported auth_mechanism `%s`, expected one of %s", ErrInvalidConfig, c.AuthMechanism, strings.Join(authMechanisms, ", "))
+	}
+	if c.AuthMechanism == "" && c.Username == "" {
+		return nil, nil
+	}
+	return &options.Credential{
+		AuthMechanism: c.AuthMechanism,
+		AuthSource:    c.AuthSource,
+		Username:      c.Username,
+		Password:      c.Password,
+		PasswordSet:   c.Password != "",
+	}, nil
+}
+
+// mergeCredential overrides cred with the fields set in the connection string dsn, whose credential the driver parsed
+// into fromDSN. The driver fills in a default auth source when the DSN carries credentials, so only an explicit
+// authSource option overrides the configured one.
+func mergeCredential(cred options.Credential, fromDSN *options.Credential, dsn string) options.Credential {
+	if fromDSN != nil {
+		if fromDSN.AuthMechanism != "" {
+			cred.AuthMechanism = fromDSN.AuthMechanism
+		}
+		if fromDSN.AuthMechanismProperties != nil {
+			cred.AuthMechanismProperties = fromDSN.AuthMechanismProperties
+		}
+		if fromDSN.Username != "" {
+			cred.Username = fromDSN.Username
+		}
+		if fromDSN.PasswordSet {
+			cred.Password = fromDSN.Password
+			cred.PasswordSet = true
+		}
+		if cred.AuthSource == "" {
+			cred.AuthSource = fromDSN.AuthSource
+		}
+	}
+	if source, ok := dsnOption(dsn, "authSource"); ok {
+		cred.AuthSource = source
+	}
+	return cred
+}
+
+type authInfo struct {
+	AuthInfo struct {
+		AuthenticatedUsers []struct {
+			User string `bson:"user"`
+			DB   string `bson:"db"`
+		} `bson:"authenticatedUsers"`
+		AuthenticatedUserRoles []struct {
+			Role string `bson:"role"`
+			DB   string `bson:"db"`
+		} `bson:"authenticatedUserRoles"`
+	} `bson:"authInfo"`
+}
+
+func (db *Database) connectionStatus(ctx context.Context) (authInfo, error) {
+	var info authInfo
+	err := db.Database.RunCommand(ctx, bson.D{{Key: "connectionStatus", Value: 1}}).Decode(&info)
+	return info, err
+}
+
+func (db *Database) VerifyAuth(ctx context.Context) error {
+	info, err := db.connectionStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if len(info.AuthInfo.AuthenticatedUsers) == 0 {
+		return ErrNotAuthenticated
+	}
+
+	roles := info.AuthInfo.AuthenticatedUserRoles
+	if len(roles) == 0 {
+		return fmt.Errorf("%w: user `%s` has no roles", ErrNotAuthorized, info.AuthInfo.AuthenticatedUsers[0].User)
+	}
+
+	var missing []string
+	for _, expected := range db.cfg.ExpectedRoles {
+		name, roleDB, scoped := strings.Cut(expected, "@")
+
+		found := false
+		for _, r := range roles {
+			if r.Role == name && (!scoped || r.DB == roleDB) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, expected)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrNotAuthorized, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// requireAuth fails with ErrNotAuthenticated when the server accepted the connection without authentication.
+func (db *Database) requireAuth(ctx context.Context) error {
+	info, err := db.connectionStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if len(info.AuthInfo.AuthenticatedUsers) == 0 {
+		return fmt.Errorf("%w: the server accepted an anonymous connection", ErrNotAuthenticated)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package dbmongo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// autoHintTimeout bounds the $indexStats lookup refreshing the AutoHint of a collection.
+const autoHintTimeout = 5 * time.Second
+
+// hintCache holds the AutoHint index per namespace, keyed by database and collection since the cache is shared by the
+// ForDatabase and WithReadPreference clones of a handle, an empty name while the lookup is in flight or when the
+// collection has no usable index.
+type hintCache struct {
+	mu    sync.Mutex
+	hints map[string]string
+}
+
+// lookup returns the cached hint of the namespace, reserving the entry and reporting false on a miss. A nil cache,
+// as in a Database not built by NewDatabase, never misses so that no lookup starts.
+func (c *hintCache) lookup(ns string) (string, bool) {
+	if c == nil {
+		return "", true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hint, ok := c.hints[ns]
+	if !ok {
+		if c.hints == nil {
+			c.hints = map[string]string{}
+		}
+		c.hints[ns] = ""
+	}
+	return hint, ok
+}
+
+func (c *hintCache) store(ns, hint string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.hints == nil {
+		c.hints = map[string]string{}
+	}
+	c.hints[ns] = hint
+}
+
+func (c *hintCache) forget(ns string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.hints, ns)
+}
+
+func (c *hintCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hints = nil
+}
+
+func (db *Database) ClearAutoHints() {
+	db.hints.clear()
+}
+
+// namespace returns the full name of the collection in db.
+func (db *Database) namespace(collection string) string {
+	return db.Name() + "." + collection
+}
+
+// autoHint returns the cached AutoHint of the collection. A miss starts a background lookup and yields no hint, so
+// finds never wait for $indexStats.
+func (db *Database) autoHint(collection string) string {
+	hint, ok := db.hints.lookup(db.namespace(collection))
+	if !ok {
+		go db.refreshAutoHint(collection)
+	}
+	return hint
+}
+
+// refreshAutoHint caches the most used index of the collection according to $indexStats.
+func (db *Database) refreshAutoHint(collection string) {
+	ctx, cancel := context.WithTimeout(context.Background(), autoHintTimeout)
+	defer cancel()
+
+	stats, err := db.IndexUsage(ctx, collection)
+	if err != nil {
+		// retried by the next find
+		db.hints.forget(db.namespace(collection))
+		db.logger().Debug("auto hint lookup failed", "collection", collection, "error", err)
+		return
+	}
+
+	var best IndexStat
+	for _, s := range stats {
+		if s.Accesses > best.Accesses {
+			best = s
+		}
+	}
+	db.hints.store(db.namespace(collection), best.Name)
+}
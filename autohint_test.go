@@ -0,0 +1,29 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAutoHintIsPerDatabase(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, Config{DSN: unreachableDSN, AutoHint: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+
+	db.hints.store(db.namespace("users"), "email_1")
+
+	if o := findOptions(db, "users", nil); o.Hint != "email_1" {
+		t.Errorf("parent hint = %v, want email_1", o.Hint)
+	}
+
+	tenant := db.ForDatabase("tenant2")
+	if o := findOptions(tenant, "users", nil); o.Hint != nil {
+		t.Errorf("tenant hint = %v, want none until its own indexes are looked up", o.Hint)
+	}
+	if o := findOptions(db.WithReadPreference(nil), "users", nil); o.Hint != "email_1" {
+		t.Errorf("same database clone hint = %v, want email_1", o.Hint)
+	}
+}
@@ -0,0 +1,42 @@
+package dbmongo
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudget is a token bucket limiting the retries of a channel: it holds up to max tokens and refills max tokens
+// per second. A nil budget allows every retry.
+type retryBudget struct {
+	mu     sync.Mutex
+	max    float64
+	tokens float64
+	last   time.Time
+}
+
+func newRetryBudget(perSecond int) *retryBudget {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &retryBudget{max: float64(perSecond), tokens: float64(perSecond), last: time.Now()}
+}
+
+// allow takes a token, reporting false when the budget is exhausted.
+func (b *retryBudget) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.max, b.tokens+now.Sub(b.last).Seconds()*b.max)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
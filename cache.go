@@ -0,0 +1,60 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrNotWiredTiger = errors.New("mongodb storage engine is not WiredTiger")
+
+type CacheStats struct {
+	// BytesInCache is the size of the data currently in the cache.
+	BytesInCache int64
+	// MaxBytes is the configured cache size.
+	MaxBytes int64
+	// DirtyBytes is the size of the modified data in the cache not yet written to disk.
+	DirtyBytes int64
+	// EvictedModified and EvictedUnmodified count the pages evicted from the cache since the server started.
+	EvictedModified   int64
+	EvictedUnmodified int64
+}
+
+func (db *Database) CacheStats(ctx context.Context) (*CacheStats, error) {
+	var status struct {
+		StorageEngine struct {
+			Name string `bson:"name"`
+		} `bson:"storageEngine"`
+		WiredTiger *struct {
+			Cache struct {
+				BytesInCache      int64 `bson:"bytes currently in the cache"`
+				MaxBytes          int64 `bson:"maximum bytes configured"`
+				DirtyBytes        int64 `bson:"tracked dirty bytes in the cache"`
+				EvictedModified   int64 `bson:"modified pages evicted"`
+				EvictedUnmodified int64 `bson:"unmodified pages evicted"`
+			} `bson:"cache"`
+		} `bson:"wiredTiger"`
+	}
+	if err := db.serverStatus(ctx, &status); err != nil {
+		return nil, err
+	}
+	if status.WiredTiger == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotWiredTiger, status.StorageEngine.Name)
+	}
+
+	c := status.WiredTiger.Cache
+	return &CacheStats{
+		BytesInCache:      c.BytesInCache,
+		MaxBytes:          c.MaxBytes,
+		DirtyBytes:        c.DirtyBytes,
+		EvictedModified:   c.EvictedModified,
+		EvictedUnmodified: c.EvictedUnmodified,
+	}, nil
+}
+
+// serverStatus decodes the serverStatus of the server into v.
+func (db *Database) serverStatus(ctx context.Context, v any) error {
+	return db.admin().RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(v)
+}
@@ -0,0 +1,70 @@
+package dbmongo
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const (
+	// churnWindow is the rolling window ChurnStats reports over.
+	churnWindow = 5 * time.Minute
+	// churnBucket is the granularity the window rolls at.
+	churnBucket = 10 * time.Second
+)
+
+type ChurnStat struct {
+	// Created and Closed count the pooled connections created and closed during the window.
+	Created int64
+	Closed  int64
+	Window  time.Duration
+}
+
+type churnCounts struct {
+	start           time.Time
+	created, closed int64
+}
+
+// churnTracker counts connection creations and closures in buckets of churnBucket covering churnWindow.
+type churnTracker struct {
+	mu      sync.Mutex
+	buckets [churnWindow / churnBucket]churnCounts
+}
+
+func (t *churnTracker) observe(e *event.PoolEvent) {
+	if e.Type != event.ConnectionCreated && e.Type != event.ConnectionClosed {
+		return
+	}
+
+	start := time.Now().Truncate(churnBucket)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := &t.buckets[int(start.UnixNano()/int64(churnBucket))%len(t.buckets)]
+	if !b.start.Equal(start) {
+		*b = churnCounts{start: start}
+	}
+	if e.Type == event.ConnectionCreated {
+		b.created++
+	} else {
+		b.closed++
+	}
+}
+
+func (t *churnTracker) stat() ChurnStat {
+	since := time.Now().Add(-churnWindow)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stat := ChurnStat{Window: churnWindow}
+	for _, b := range t.buckets {
+		if b.start.After(since) {
+			stat.Created += b.created
+			stat.Closed += b.closed
+		}
+	}
+	return stat
+}
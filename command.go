@@ -0,0 +1,131 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrReadOnly          = errors.New("mongodb database is read-only")
+	ErrCommandNotAllowed = errors.New("mongodb command is not allowed on this channel")
+)
+
+// writeCommands holds the lower-cased names of the commands rejected on read-only databases.
+var writeCommands = map[string]struct{}{
+	"insert":           {},
+	"update":           {},
+	"delete":           {},
+	"findandmodify":    {},
+	"create":           {},
+	"createindexes":    {},
+	"collmod":          {},
+	"drop":             {},
+	"dropdatabase":     {},
+	"dropindexes":      {},
+	"renamecollection": {},
+	"compact":          {},
+	"createuser":       {},
+	"updateuser":       {},
+	"dropuser":         {},
+}
+
+func (db *Database) RunCommand(ctx context.Context, runCommand any, opts ...*options.RunCmdOptions) *mongo.SingleResult {
+	if err := db.checkCommand(runCommand); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return db.Database.RunCommand(ctx, runCommand, opts...)
+}
+
+func (db *Database) RunCommandCursor(ctx context.Context, runCommand any, opts ...*options.RunCmdOptions) (*mongo.Cursor, error) {
+	if err := db.checkCommand(runCommand); err != nil {
+		return nil, err
+	}
+	return db.Database.RunCommandCursor(ctx, runCommand, opts...)
+}
+
+func (db *Database) RunAdminCommand(ctx context.Context, command any, opts ...*options.RunCmdOptions) *mongo.SingleResult {
+	if err := db.checkCommand(command); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return db.admin().RunCommand(ctx, command, opts...)
+}
+
+func (db *Database) Drop(ctx context.Context) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+	return db.Database.Drop(ctx)
+}
+
+func (db *Database) CreateCollection(ctx context.Context, name string, opts ...*options.CreateCollectionOptions) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+	return db.Database.CreateCollection(ctx, name, opts...)
+}
+
+func (db *Database) CreateView(ctx context.Context, viewName, viewOn string, pipeline any, opts ...*options.CreateViewOptions) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+	return db.Database.CreateView(ctx, viewName, viewOn, pipeline, opts...)
+}
+
+func (db *Database) checkWrite() error {
+	if db.cfg.ReadOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// checkWrite rejects writes through the generic helpers when db is read-only.
+func checkWrite(db DB) error {
+	if d, ok := db.(interface{ checkWrite() error }); ok {
+		return d.checkWrite()
+	}
+	return nil
+}
+
+func (db *Database) checkCommand(cmd any) error {
+	name := commandName(cmd)
+	if len(db.cfg.AllowedCommands) > 0 && !slices.ContainsFunc(db.cfg.AllowedCommands, func(allowed string) bool {
+		return strings.EqualFold(allowed, name)
+	}) {
+		return fmt.Errorf("%w: `%s`", ErrCommandNotAllowed, name)
+	}
+	if !db.cfg.ReadOnly {
+		return nil
+	}
+	if _, ok := writeCommands[strings.ToLower(name)]; ok {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// commandName returns the first key of the command document, which names the command.
+func commandName(cmd any) string {
+	if d, ok := cmd.(bson.D); ok {
+		if len(d) == 0 {
+			return ""
+		}
+		return d[0].Key
+	}
+
+	raw, err := bson.Marshal(cmd)
+	if err != nil {
+		return ""
+	}
+
+	elem, err := bson.Raw(raw).IndexErr(0)
+	if err != nil {
+		return ""
+	}
+	return elem.Key()
+}
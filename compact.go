@@ -0,0 +1,20 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrMongos = errors.New("operation is not supported through mongos")
+
+func (db *Database) Compact(ctx context.Context, collection string) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+	if db.requireMongos(ctx) == nil {
+		return ErrMongos
+	}
+	return db.Database.RunCommand(ctx, bson.D{{Key: "compact", Value: collection}}).Err()
+}
@@ -1,8 +1,223 @@
 package dbmongo
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidConfig = errors.New("invalid mongo config")
+
 type Channels map[string]Config
 
 type Config struct {
 	DSN  string `mapstructure:"dsn" json:"dsn,omitempty" yaml:"dsn,omitempty"`
 	Ping bool   `mapstructure:"ping" json:"ping,omitempty" yaml:"ping,omitempty"`
+
+	// DatabaseName selects the database instead of the DSN path, which then may be omitted.
+	DatabaseName string `mapstructure:"database" json:"database,omitempty" yaml:"database,omitempty"`
+
+	// ConnectRetries is the number of times a DNS failure resolving a mongodb+srv:// DSN is retried at connect.
+	ConnectRetries int `mapstructure:"connect_retries" json:"connect_retries,omitempty" yaml:"connect_retries,omitempty"`
+
+	// MaxConnIdleTime closes pooled connections that stayed idle for longer, so that idle workloads release their
+	// connections. Zero keeps the driver default of never pruning.
+	MaxConnIdleTime time.Duration `mapstructure:"max_conn_idle_time" json:"max_conn_idle_time,omitempty" yaml:"max_conn_idle_time,omitempty"`
+
+	// ReadPreference is the default read preference of the channel.
+	ReadPreference ReadPreferenceConfig `mapstructure:"read_preference" json:"read_preference,omitempty" yaml:"read_preference,omitempty"`
+
+	// RequireCollections lists collections that must exist when the database is created.
+	RequireCollections []string `mapstructure:"require_collections" json:"require_collections,omitempty" yaml:"require_collections,omitempty"`
+
+	// AllowDiskUse lets aggregations run through Database.Aggregate spill to temporary files on the server once they
+	// exceed the per-stage memory limit, unless the call sets AllowDiskUse itself. Spilling trades memory pressure on
+	// the server for disk I/O and slower stages.
+	AllowDiskUse bool `mapstructure:"allow_disk_use" json:"allow_disk_use,omitempty" yaml:"allow_disk_use,omitempty"`
+
+	// LogLevel gates what the channel logs: silent, error (the default, command failures only), info or debug (every
+	// command start and finish).
+	LogLevel string `mapstructure:"log_level" json:"log_level,omitempty" yaml:"log_level,omitempty"`
+
+	// DefaultSort is the sort the find helpers such as Stream and FindProjected apply when the call sets none, which
+	// keeps paginated results stable. An explicit sort always overrides it.
+	DefaultSort bson.D `mapstructure:"default_sort" json:"default_sort,omitempty" yaml:"default_sort,omitempty"`
+
+	// ServerMonitoringMode selects how servers are monitored: stream, poll or auto (the driver default). Serverless
+	// and FaaS deployments should use poll to avoid the extra streaming connection per server.
+	ServerMonitoringMode string `mapstructure:"server_monitoring_mode" json:"server_monitoring_mode,omitempty" yaml:"server_monitoring_mode,omitempty"`
+
+	// BypassDocumentValidation makes the write helpers skip collection validators by default, for seeding or fixing
+	// data that temporarily violates them. The user needs the bypassDocumentValidation privilege.
+	BypassDocumentValidation bool `mapstructure:"bypass_document_validation" json:"bypass_document_validation,omitempty" yaml:"bypass_document_validation,omitempty"`
+
+	// HealthCheckInterval starts a background loop pinging the database at this interval and logging when it becomes
+	// unreachable or recovers. After a few consecutive failed pings the handle is invalidated, so that MongoMaker
+	// replaces it with a fresh client on the next MakeMongoDB. The loop stops when the database is closed. Zero
+	// disables it.
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval" json:"health_check_interval,omitempty" yaml:"health_check_interval,omitempty"`
+
+	// ForbiddenDSNOptions rejects DSNs carrying any of the listed options, either by name (tlsInsecure) or as an exact
+	// name=value pair (tlsInsecure=true), to enforce connection policies centrally.
+	ForbiddenDSNOptions []string `mapstructure:"forbidden_dsn_options" json:"forbidden_dsn_options,omitempty" yaml:"forbidden_dsn_options,omitempty"`
+
+	// SlowCheckoutThreshold logs a warning, visible from log level info, whenever an operation waits longer than this
+	// for a pooled connection, telling a pool that is too small apart from a slow database. Zero disables it.
+	SlowCheckoutThreshold time.Duration `mapstructure:"slow_checkout_threshold" json:"slow_checkout_threshold,omitempty" yaml:"slow_checkout_threshold,omitempty"`
+
+	// ExpectedRoles lists the roles Database.VerifyAuth requires the connected user to hold, either by name (readWrite)
+	// or scoped to a database (readWrite@app).
+	ExpectedRoles []string `mapstructure:"expected_roles" json:"expected_roles,omitempty" yaml:"expected_roles,omitempty"`
+
+	// DefaultHints maps collection names to the index name the find helpers hint when the call sets no hint, for
+	// queries the planner routes to a suboptimal index. Hints are passed through without checking that the index
+	// exists.
+	DefaultHints map[string]string `mapstructure:"default_hints" json:"default_hints,omitempty" yaml:"default_hints,omitempty"`
+
+	// DefaultExcludeFields maps collection names to fields, typically large blobs, the find helpers leave out of the
+	// results when the call sets no projection. An explicit projection replaces the exclusion entirely.
+	DefaultExcludeFields map[string][]string `mapstructure:"default_exclude_fields" json:"default_exclude_fields,omitempty" yaml:"default_exclude_fields,omitempty"`
+
+	// TransactionDefaults sets the read concern, write concern and read preference Database.WithTransaction uses
+	// unless the call sets them, for example to enforce snapshot reads and majority writes on every transaction.
+	TransactionDefaults TransactionDefaults `mapstructure:"transaction_defaults" json:"transaction_defaults,omitempty" yaml:"transaction_defaults,omitempty"`
+
+	// Auth sets the credentials when they are not part of the DSN, for example to keep the password out of it.
+	Auth AuthConfig `mapstructure:"auth" json:"auth,omitempty" yaml:"auth,omitempty"`
+
+	// WaitForWritable makes NewDatabase wait until a writable primary is available before returning, for ordered
+	// startup. WaitTimeout bounds the wait; zero waits as long as the context allows.
+	WaitForWritable bool          `mapstructure:"wait_for_writable" json:"wait_for_writable,omitempty" yaml:"wait_for_writable,omitempty"`
+	WaitTimeout     time.Duration `mapstructure:"wait_timeout" json:"wait_timeout,omitempty" yaml:"wait_timeout,omitempty"`
+
+	// WriteConcern is the default write concern of the channel: majority, a number of members or a custom write
+	// concern name. WriteConcernTimeout bounds how long the server waits for the write concern to be satisfied, so
+	// majority writes fail with a write concern error instead of blocking while members are down; zero waits
+	// indefinitely. The operation context deadline still applies and whichever expires first ends the wait, and a
+	// write concern timeout does not undo writes already applied on the primary. A write concern in the DSN replaces
+	// both settings.
+	WriteConcern        string        `mapstructure:"write_concern" json:"write_concern,omitempty" yaml:"write_concern,omitempty"`
+	WriteConcernTimeout time.Duration `mapstructure:"write_concern_timeout" json:"write_concern_timeout,omitempty" yaml:"write_concern_timeout,omitempty"`
+
+	// RequireAuth makes NewDatabase fail with ErrNotAuthenticated when the server accepts the connection without
+	// authentication, which points at a cluster with access control disabled.
+	RequireAuth bool `mapstructure:"require_auth" json:"require_auth,omitempty" yaml:"require_auth,omitempty"`
+
+	// AutoHint makes the find helpers hint the most used index of the collection, according to $indexStats, on finds
+	// setting neither a hint nor a projection, for hot read paths where the planner occasionally regresses. The
+	// index is looked up in the background on the first find of each collection and cached until
+	// Database.ClearAutoHints; it is hinted whatever the filter, which forces a full index scan on queries the index
+	// does not serve, so only enable it for collections queried one way. DefaultHints take precedence. Off by
+	// default.
+	AutoHint bool `mapstructure:"auto_hint" json:"auto_hint,omitempty" yaml:"auto_hint,omitempty"`
+
+	// AllowedCommands restricts RunCommand, RunCommandCursor and RunAdminCommand to the listed commands, matched
+	// case-insensitively against the first key of the command document; others fail with ErrCommandNotAllowed. The
+	// commands the package issues internally are not restricted. Empty allows every command.
+	AllowedCommands []string `mapstructure:"allowed_commands" json:"allowed_commands,omitempty" yaml:"allowed_commands,omitempty"`
+
+	// Eager makes the plugin connect the channel when it starts serving instead of on first use, so that an
+	// unreachable database fails the service startup. With Optional, a failing eager connection is only logged and
+	// retried on first use, for databases the service can run without.
+	Eager    bool `mapstructure:"eager" json:"eager,omitempty" yaml:"eager,omitempty"`
+	Optional bool `mapstructure:"optional" json:"optional,omitempty" yaml:"optional,omitempty"`
+
+	// ConnectTimeout bounds how long NewDatabase may take to connect and run its startup checks, such as Ping, which
+	// otherwise wait for the context. Zero leaves the context as the only bound.
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout" json:"connect_timeout,omitempty" yaml:"connect_timeout,omitempty"`
+
+	// ServerSelectionTimeout bounds how long an operation waits for a suitable server, instead of the driver default
+	// of 30s, so operations fail fast while the deployment is unavailable. serverSelectionTimeoutMS in the DSN takes
+	// precedence. Zero keeps the driver default. This is the client-level setting: WithSelectionTimeout and the
+	// SelectionTimeout write option replace it, shorter or longer, for single helper calls.
+	ServerSelectionTimeout time.Duration `mapstructure:"server_selection_timeout" json:"server_selection_timeout,omitempty" yaml:"server_selection_timeout,omitempty"`
+
+	// Metrics enables the pool monitor tracking connection churn, reported by MongoMaker.ChurnStats. It adds a small
+	// cost to every connection creation and closure.
+	Metrics bool `mapstructure:"metrics" json:"metrics,omitempty" yaml:"metrics,omitempty"`
+
+	// MaxPoolSize caps the connections of the pool kept per server, zero meaning unlimited, and MinPoolSize is the
+	// number of connections kept open even when idle. Unset values keep the driver defaults of 100 and 0, and
+	// maxPoolSize and minPoolSize in the DSN take precedence.
+	MaxPoolSize *uint64 `mapstructure:"max_pool_size" json:"max_pool_size,omitempty" yaml:"max_pool_size,omitempty"`
+	MinPoolSize *uint64 `mapstructure:"min_pool_size" json:"min_pool_size,omitempty" yaml:"min_pool_size,omitempty"`
+
+	// RetryBudget caps the retries Database.Retry and Database.RetryOnElection may issue across the channel to this
+	// many per second, so stacked retries cannot amplify the load of an outage. Once exhausted, the underlying error is
+	// returned instead of retrying. Zero leaves retries unlimited.
+	RetryBudget int `mapstructure:"retry_budget" json:"retry_budget,omitempty" yaml:"retry_budget,omitempty"`
+
+	// ReadOnly rejects write commands and write helpers with ErrReadOnly before they reach the server.
+	ReadOnly bool `mapstructure:"read_only" json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+func (cfg Config) Validate() error {
+	if err := checkForbiddenOptions(cfg.DSN, cfg.ForbiddenDSNOptions); err != nil {
+		return err
+	}
+	if cfg.MaxConnIdleTime < 0 {
+		return fmt.Errorf("%w: max_conn_idle_time must not be negative", ErrInvalidConfig)
+	}
+	if cfg.SlowCheckoutThreshold < 0 {
+		return fmt.Errorf("%w: slow_checkout_threshold must not be negative", ErrInvalidConfig)
+	}
+	if cfg.ConnectTimeout < 0 {
+		return fmt.Errorf("%w: connect_timeout must not be negative", ErrInvalidConfig)
+	}
+	if cfg.MaxPoolSize != nil && cfg.MinPoolSize != nil && *cfg.MaxPoolSize != 0 && *cfg.MinPoolSize > *cfg.MaxPoolSize {
+		return fmt.Errorf("%w: min_pool_size must not exceed max_pool_size", ErrInvalidConfig)
+	}
+	if cfg.ServerSelectionTimeout < 0 {
+		return fmt.Errorf("%w: server_selection_timeout must not be negative", ErrInvalidConfig)
+	}
+	if cfg.WaitTimeout < 0 {
+		return fmt.Errorf("%w: wait_timeout must not be negative", ErrInvalidConfig)
+	}
+	if cfg.WriteConcernTimeout < 0 {
+		return fmt.Errorf("%w: write_concern_timeout must not be negative", ErrInvalidConfig)
+	}
+	if _, err := parseW(cfg.WriteConcern); err != nil {
+		return err
+	}
+	if cfg.RetryBudget < 0 {
+		return fmt.Errorf("%w: retry_budget must not be negative", ErrInvalidConfig)
+	}
+	if cfg.HealthCheckInterval < 0 {
+		return fmt.Errorf("%w: health_check_interval must not be negative", ErrInvalidConfig)
+	}
+	if _, err := cfg.ReadPreference.ReadPref(); err != nil {
+		return err
+	}
+	if _, err := cfg.TransactionDefaults.options(); err != nil {
+		return err
+	}
+	if _, err := cfg.Auth.credential(); err != nil {
+		return err
+	}
+	if _, err := parseLogLevel(cfg.LogLevel); err != nil {
+		return err
+	}
+	switch cfg.ServerMonitoringMode {
+	case "", options.ServerMonitoringModeAuto, options.ServerMonitoringModePoll, options.ServerMonitoringModeStream:
+	default:
+		return fmt.Errorf("%w: unknown server_monitoring_mode `%s`", ErrInvalidConfig, cfg.ServerMonitoringMode)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the config with the DSN credentials redacted so that logged configs do not leak secrets.
+// Unmarshaling is unaffected and reads the DSN as given.
+func (cfg Config) MarshalJSON() ([]byte, error) {
+	type config Config
+
+	c := config(cfg)
+	c.DSN = RedactDSN(c.DSN)
+	if c.Auth.Password != "" {
+		c.Auth.Password = redacted
+	}
+	return json.Marshal(c)
 }
@@ -0,0 +1,31 @@
+package dbmongo
+
+import "context"
+
+type ConnStats struct {
+	// Current is the number of open incoming connections, including shell and internal ones.
+	Current int64
+	// Available is the number of further incoming connections the server accepts.
+	Available int64
+	// TotalCreated counts the connections created since the server started, including closed ones.
+	TotalCreated int64
+	// Active is the number of connections with an operation in progress.
+	Active int64
+}
+
+func (db *Database) ConnectionStats(ctx context.Context) (*ConnStats, error) {
+	var status struct {
+		Connections struct {
+			Current      int64 `bson:"current"`
+			Available    int64 `bson:"available"`
+			TotalCreated int64 `bson:"totalCreated"`
+			Active       int64 `bson:"active"`
+		} `bson:"connections"`
+	}
+	if err := db.serverStatus(ctx, &status); err != nil {
+		return nil, err
+	}
+
+	c := status.Connections
+	return &ConnStats{Current: c.Current, Available: c.Available, TotalCreated: c.TotalCreated, Active: c.Active}, nil
+}
@@ -0,0 +1,110 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultBatchSize is used by the batching helpers when no positive batch size is given.
+const defaultBatchSize = 1000
+
+func (db *Database) CopyCollectionTo(ctx context.Context, srcCollection string, dst *Database, dstCollection string, batchSize int, opts ...WriteOption) (int64, error) {
+	if err := dst.checkWrite(); err != nil {
+		return 0, err
+	}
+
+	o := dst.writeOptions(opts)
+
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	var cursor *mongo.Cursor
+	err := withSelection(ctx, db, o.selectionTimeout, func(ctx context.Context) (err error) {
+		cursor, err = db.Collection(srcCollection).Find(ctx, bson.D{}, options.Find().SetBatchSize(int32(batchSize)))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	target := dst.Collection(dstCollection)
+	insertOpts := options.InsertMany().SetOrdered(!o.skipDuplicates)
+	if o.bypassDocumentValidation {
+		insertOpts.SetBypassDocumentValidation(true)
+	}
+
+	var copied int64
+	flush := func(batch []any) error {
+		var res *mongo.InsertManyResult
+		err := withSelection(ctx, dst, o.selectionTimeout, func(ctx context.Context) (err error) {
+			res, err = target.InsertMany(ctx, batch, insertOpts)
+			return err
+		})
+		if err == nil {
+			copied += int64(len(res.InsertedIDs))
+			return nil
+		}
+
+		var bwe mongo.BulkWriteException
+		if !errors.As(err, &bwe) {
+			return err
+		}
+		// the documents of the batch the server accepted are copied even when the batch fails
+		copied += insertedBefore(bwe, len(batch), !o.skipDuplicates)
+		if !o.skipDuplicates || bwe.WriteConcernError != nil || !onlyDuplicateKeys(bwe.WriteErrors) {
+			return err
+		}
+		return nil
+	}
+
+	batch := make([]any, 0, batchSize)
+	for cursor.Next(ctx) {
+		batch = append(batch, append(bson.Raw(nil), cursor.Current...))
+		if len(batch) < batchSize {
+			continue
+		}
+		if err = flush(batch); err != nil {
+			return copied, err
+		}
+		batch = batch[:0]
+	}
+	if err = cursor.Err(); err != nil {
+		return copied, err
+	}
+
+	if len(batch) > 0 {
+		if err = flush(batch); err != nil {
+			return copied, err
+		}
+	}
+	return copied, nil
+}
+
+// insertedBefore returns how many of the n documents of a failed InsertMany were written: an ordered insert stops at
+// its first write error, an unordered one skips the failed documents, and an insert failing only on its write
+// concern wrote every document.
+func insertedBefore(bwe mongo.BulkWriteException, n int, ordered bool) int64 {
+	switch {
+	case len(bwe.WriteErrors) == 0:
+		return int64(n)
+	case ordered:
+		return int64(bwe.WriteErrors[0].Index)
+	default:
+		return int64(n - len(bwe.WriteErrors))
+	}
+}
+
+func onlyDuplicateKeys(errs []mongo.BulkWriteError) bool {
+	for _, we := range errs {
+		if !mongo.IsDuplicateKeyError(we.WriteError) {
+			return false
+		}
+	}
+	return true
+}
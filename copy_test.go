@@ -0,0 +1,34 @@
+package dbmongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInsertedBefore(t *testing.T) {
+	writeErrors := func(indexes ...int) mongo.BulkWriteException {
+		var bwe mongo.BulkWriteException
+		for _, i := range indexes {
+			bwe.WriteErrors = append(bwe.WriteErrors, mongo.BulkWriteError{WriteError: mongo.WriteError{Index: i, Code: 11000}})
+		}
+		return bwe
+	}
+
+	tests := []struct {
+		name    string
+		bwe     mongo.BulkWriteException
+		ordered bool
+		want    int64
+	}{
+		{name: "ordered", bwe: writeErrors(3), ordered: true, want: 3},
+		{name: "unordered", bwe: writeErrors(1, 4), want: 8},
+		{name: "write concern only", bwe: mongo.BulkWriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}, ordered: true, want: 10},
+	}
+
+	for _, tt := range tests {
+		if got := insertedBefore(tt.bwe, 10, tt.ordered); got != tt.want {
+			t.Errorf("%s: inserted = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
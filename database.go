@@ -4,7 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
@@ -15,7 +24,16 @@ import (
 
 var _ MongoDB = (*Database)(nil)
 
-var ErrNoDB = errors.New("database name not found in URI")
+var (
+	ErrNoDB           = errors.New("database name not found in URI")
+	ErrConnectTimeout = errors.New("mongo connect timed out")
+)
+
+// srvRetryBackoff is the initial pause between SRV resolution attempts.
+const srvRetryBackoff = 250 * time.Millisecond
+
+// mongoConnect connects the clients, replaced in tests to simulate connection failures.
+var mongoConnect = mongo.Connect
 
 const (
 	ErrMsgClient   = "failed to create mongodb client due to error: %w"
@@ -55,6 +73,33 @@ type MongoDB interface {
 	// documentation).
 	Watch(ctx context.Context, pipeline any, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error)
 
+	// WatchFrom returns a change stream for the database starting at the operation time ts, for consumers that lost
+	// their resume token but know the time of the last processed event. The timestamp must not be zero and overrides
+	// any start time in opts; the pipeline and opts parameters otherwise behave as for Watch.
+	WatchFrom(ctx context.Context, ts primitive.Timestamp, pipeline any, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error)
+
+	// WatchDebounced watches the database like Watch and delivers the change events to handler in batches: a batch
+	// starts with the first event after the previous one and collects the events of the following window. Events
+	// collected when the stream fails or ctx is done are not delivered. It runs until ctx is done, the stream fails or
+	// handler returns an error, which is returned.
+	WatchDebounced(ctx context.Context, pipeline any, window time.Duration, handler func(events []bson.Raw) error, opts ...*options.ChangeStreamOptions) error
+
+	// WatchForever watches the database like Watch and invokes handler for every change event, recreating the stream
+	// from the last resume token whenever it fails with a resumable error. The token advances with every handled event
+	// and, on a quiet stream, with every empty batch; it replaces any start options in opts and is only kept in
+	// memory. It returns when ctx is done, handler returns an error, the stream fails with a non-resumable error or
+	// is invalidated, for example by dropping the database, in which case it returns nil.
+	WatchForever(ctx context.Context, pipeline any, handler func(bson.Raw) error, opts ...*options.ChangeStreamOptions) error
+
+	// WatchCluster returns a change stream for all changes in every database of the deployment the channel is
+	// connected to. See https://www.mongodb.com/docs/manual/changeStreams/ for more information about change streams.
+	//
+	// The Client must be configured with read concern majority or no read concern for a change stream to be created
+	// successfully.
+	//
+	// The pipeline and opts parameters behave as for Watch.
+	WatchCluster(ctx context.Context, pipeline any, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error)
+
 	// Aggregate executes an aggregate command the database. This requires MongoDB version >= 3.6 and driver version >=
 	// 1.1.0.
 	//
@@ -69,6 +114,16 @@ type MongoDB interface {
 	// For more information about the command, see https://www.mongodb.com/docs/manual/reference/command/aggregate/.
 	Aggregate(ctx context.Context, pipeline any, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
 
+	// AggregateDocuments runs pipeline as a database-level aggregation over docs instead of a collection, by prepending
+	// a $documents stage, which requires MongoDB 5.1 or later. Aggregate accepts such pipelines as well; this saves
+	// building the stage. A nil pipeline returns docs unchanged.
+	AggregateDocuments(ctx context.Context, docs []bson.M, pipeline any) (*mongo.Cursor, error)
+
+	// ValidatePipeline checks that pipeline parses and plans on the collection by explaining it with queryPlanner
+	// verbosity, returning the server parse or validation error. The stages are not executed and no data is read or
+	// written, but the server is queried, so it needs a reachable deployment with the collection's namespace.
+	ValidatePipeline(ctx context.Context, collection string, pipeline any) error
+
 	// RunCommand executes the given command against the database. This function does not obey the Database's read
 	// preference. To specify a read preference, the RunCmdOptions.ReadPreference option must be used.
 	//
@@ -99,6 +154,16 @@ type MongoDB interface {
 	// - maxTimeMS when Timeout is set on the Client
 	RunCommandCursor(ctx context.Context, runCommand any, opts ...*options.RunCmdOptions) (*mongo.Cursor, error)
 
+	// RunAdminCommand executes the given command against the admin database of the client, for server management
+	// commands such as replSetGetStatus or fsync that cannot run against the channel database. Like RunCommand, it does
+	// not obey the Database's read preference.
+	//
+	// The command parameter must be a document for the command to be executed. It cannot be nil.
+	// This must be an order-preserving type such as bson.D. Map types such as bson.M are not valid.
+	//
+	// The opts parameter can be used to specify options for this operation (see the options.RunCmdOptions documentation).
+	RunAdminCommand(ctx context.Context, command any, opts ...*options.RunCmdOptions) *mongo.SingleResult
+
 	// ListCollectionSpecifications executes a listCollections command and returns a slice of CollectionSpecification
 	// instances representing the collections in the database.
 	//
@@ -171,6 +236,217 @@ type MongoDB interface {
 	// documentation).
 	CreateView(ctx context.Context, viewName, viewOn string, pipeline any, opts ...*options.CreateViewOptions) error
 
+	// ListViews lists the views in the database matching filter, together with the collection each view is defined on
+	// and its pipeline. A nil filter lists every view.
+	ListViews(ctx context.Context, filter any) ([]ViewInfo, error)
+
+	// CopyCollectionTo streams every document of srcCollection into dstCollection of dst in batches of batchSize,
+	// preserving _ids, and returns the number of documents copied. Duplicate keys in the destination fail the copy
+	// unless SkipDuplicates is given.
+	CopyCollectionTo(ctx context.Context, srcCollection string, dst *Database, dstCollection string, batchSize int, opts ...WriteOption) (int64, error)
+
+	// ReadFromAllMembers runs findOne against every readable replica set member over a direct connection and returns
+	// each host's document, which helps diagnosing replication lag and stale reads. Hosts without a match map to nil and
+	// the results of reachable hosts are returned along with the errors of the others. It fails with ErrNotReplicaSet
+	// on other topologies.
+	ReadFromAllMembers(ctx context.Context, collection string, filter any) (map[string]bson.Raw, error)
+
+	// SetProfilingLevel sets the database profiler level (0 off, 1 slow operations, 2 all operations) and the slow
+	// operation threshold in milliseconds.
+	SetProfilingLevel(ctx context.Context, level int, slowMs int) error
+
+	// GetProfilingLevel returns the current profiler level and slow operation threshold in milliseconds.
+	GetProfilingLevel(ctx context.Context) (int, int, error)
+
+	// WithDeadline runs fn with a context bounded by d. Errors caused by the deadline are wrapped with the database name
+	// and the exceeded duration.
+	WithDeadline(ctx context.Context, d time.Duration, fn func(context.Context) error) error
+
+	// ReplicationLag returns how far the furthest-behind secondary trails the primary, based on the member optimes
+	// reported by replSetGetStatus. It fails with ErrNotReplicaSet on other topologies.
+	ReplicationLag(ctx context.Context) (time.Duration, error)
+
+	// WithReadPreference returns a copy of the handle that reads with rp, leaving the original untouched. The copy is
+	// cheap: it shares the client and its connection pool, so it is meant to be scoped to a block of operations and
+	// must not be closed on its own.
+	WithReadPreference(rp *readpref.ReadPref) *Database
+
+	// ForDatabase returns a handle on the name database of the same client, for multi-tenant channels switching
+	// databases per tenant. It inherits the read and write settings and the channel configuration of the handle and
+	// shares its client and connection pool, so it must not be closed on its own.
+	ForDatabase(name string) *Database
+
+	// InsertOneWithID inserts doc and returns its _id. A new ObjectID is generated and set on doc when it has no _id;
+	// an existing _id must be an ObjectID or ErrNotObjectID is returned.
+	InsertOneWithID(ctx context.Context, collection string, doc bson.M, opts ...WriteOption) (primitive.ObjectID, error)
+
+	// InsertManyBatched inserts docs in batches of batchSize. Ordered inserts stop at the first rejected document,
+	// unordered ones keep going. The result reports the inserted count and, per failed batch, which documents were
+	// rejected and why; the returned error aggregates the batch failures.
+	InsertManyBatched(ctx context.Context, collection string, docs []any, batchSize int, ordered bool, opts ...WriteOption) (*BatchInsertResult, error)
+
+	// Retry calls fn up to attempts times while it fails with a transient error, like the package level Retry, within
+	// the channel RetryBudget.
+	Retry(ctx context.Context, attempts int, backoff time.Duration, fn func(context.Context) error) error
+
+	// RetryOnElection calls fn until it stops failing because the replica set has no writable primary or the member
+	// serving it is shutting down, backing off briefly between attempts. Other errors are returned immediately; when ctx
+	// is done or the channel RetryBudget is exhausted the last error is returned.
+	RetryOnElection(ctx context.Context, fn func(context.Context) error) error
+
+	// WaitForPrimary blocks until a writable primary answers, backing off between attempts. When ctx is done first it
+	// returns the context error together with the last failure, ErrNoPrimary when the servers answered.
+	WaitForPrimary(ctx context.Context) error
+
+	// UpdateWithVersion applies update to the document with the given _id only if its VersionField still equals
+	// expectedVersion, incrementing the version in the same write. It returns ErrVersionConflict when the document is
+	// missing or was changed concurrently.
+	UpdateWithVersion(ctx context.Context, collection string, id any, expectedVersion int64, update bson.M, opts ...WriteOption) error
+
+	// IndexUsage returns how often each index of the collection was used, per host, according to $indexStats. Indexes
+	// that are never accessed are candidates for removal. The counters reset when the server restarts and when the
+	// index is rebuilt, so they cover the period starting at Since only.
+	IndexUsage(ctx context.Context, collection string) ([]IndexStat, error)
+
+	// ShardDistribution returns the number of documents and the data size in bytes the collection holds on each shard,
+	// to diagnose shard imbalance. It fails with ErrNotSharded unless connected through mongos.
+	ShardDistribution(ctx context.Context, collection string) ([]ShardChunk, error)
+
+	// TruncateDatabase drops every collection of the database except the system ones, timeseries collections
+	// included, keeping the database and its views. Indexes and validators go with the collections. The drop errors of
+	// all collections are aggregated.
+	TruncateDatabase(ctx context.Context) error
+
+	// TruncateCollection removes every document of the collection. Dropping and recreating the collection with its
+	// options and indexes is much faster than deleting documents one by one and is preferred, but it briefly leaves
+	// the collection missing, rebuilds the indexes and changes the collection UUID, which invalidates change streams on
+	// it. Sharded clusters, views and collections whose definition cannot be read fall back to DeleteMany.
+	TruncateCollection(ctx context.Context, collection string) error
+
+	// WithTransaction runs fn in a transaction on a new session, committing it when fn succeeds and retrying it on
+	// transient transaction errors as described for mongo.Session.WithTransaction. The operations in fn must use
+	// sessCtx to take part in the transaction. WithTransactionTyped avoids asserting the result type.
+	WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (any, error), opts ...*options.TransactionOptions) (any, error)
+
+	// CausalScope runs fn with a context carrying a new causally consistent session, so the operations fn issues with
+	// that context read their own writes, including on secondaries. The guarantee only holds when the reads use
+	// majority read concern and the writes majority write concern. The session ends when fn returns.
+	CausalScope(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// SampleShardKeyCardinality estimates the cardinality of field as a candidate shard key by counting its distinct
+	// values in a random sample of sampleSize documents. The result is an estimate bounded by the sample size, not an
+	// exact count.
+	SampleShardKeyCardinality(ctx context.Context, collection, field string, sampleSize int) (int64, error)
+
+	// EnsureIndexes creates the indexes of models on the collection. Indexes that already exist with the same name,
+	// keys and options (unique, sparse, partial filter, TTL and collation), for example because another instance
+	// created them concurrently, are treated as created; an existing index that differs in any of them still fails
+	// with the server's error. Unnamed models are matched by the name the driver generates for them.
+	EnsureIndexes(ctx context.Context, collection string, models []mongo.IndexModel) error
+
+	// ServerTime returns the local time, in UTC, of the server answering a hello command, to detect clock skew that
+	// affects TTL indexes and change streams.
+	ServerTime(ctx context.Context) (time.Time, error)
+
+	// SetBalancerState starts or stops the balancer of a sharded cluster, for example around a maintenance window.
+	// Stopping waits for an in-progress chunk migration to finish. It fails with ErrNotSharded unless connected through
+	// mongos and with ErrReadOnly on a read-only channel.
+	SetBalancerState(ctx context.Context, enabled bool) error
+
+	// BalancerState reports whether the balancer of a sharded cluster is enabled. It fails with ErrNotSharded unless
+	// connected through mongos.
+	BalancerState(ctx context.Context) (bool, error)
+
+	// CollectionOnHost returns a handle to the collection over a direct connection to host, which must be a member of
+	// the replica set or, for other deployments, one of the DSN hosts; otherwise it fails with ErrUnknownHost. The
+	// returned func disconnects the dedicated client and must be called once the handle is no longer used.
+	CollectionOnHost(ctx context.Context, host, collection string) (*mongo.Collection, func() error, error)
+
+	// GetValidator returns the validator document, validation level and validation action of the collection as stored
+	// in its options. A collection without a validator yields a nil document and the levels set explicitly, if any;
+	// a missing collection fails with ErrMissingCollections.
+	GetValidator(ctx context.Context, collection string) (bson.M, string, string, error)
+
+	// Compact runs the compact command on the collection to release the storage freed by large deletes. Depending on
+	// the server version and storage engine it may block writes to the database while it runs, so it belongs in a
+	// maintenance window; it requires the compact privilege and only reclaims space on the member it runs on. It fails
+	// with ErrMongos when connected through mongos and with ErrReadOnly on a read-only channel.
+	Compact(ctx context.Context, collection string) error
+
+	// CacheStats reads the WiredTiger cache metrics of the server from serverStatus, to watch the memory pressure that
+	// precedes performance degradation. It is specific to WiredTiger and fails with ErrNotWiredTiger on other storage
+	// engines, as well as through mongos, which reports no storage engine.
+	CacheStats(ctx context.Context) (*CacheStats, error)
+
+	// ConnectionStats reads the connection counters of the server from serverStatus. They cover every client of the
+	// server answering the command, not only this one; compare them with the pool metrics of the channel to tell
+	// whether connection growth is client-side or server-side.
+	ConnectionStats(ctx context.Context) (*ConnStats, error)
+
+	// TTLMonitorStatus reads the TTL monitor counters of serverStatus, metrics.ttl.passes and deletedDocuments, and
+	// returns the time of the last pass with the number of documents the monitor removed since the server started.
+	// serverStatus only counts passes, so the time is the server time of the first sample taken through the handle
+	// that saw the current count: the pass completed by then, and the time is only as precise as the calls are
+	// frequent. It is zero while the monitor has completed no pass, for example when it is disabled, and stops
+	// advancing between calls when the monitor stalls.
+	TTLMonitorStatus(ctx context.Context) (time.Time, int64, error)
+
+	// CountOrphans sums the orphaned documents, left behind on a shard by chunk migrations, that each shard reports
+	// for the collection in the numOrphanDocs field of its $collStats storage stats. The field requires MongoDB 6.0 or
+	// later; older shards do not report it and count as zero. The counters are maintained by the range deleter and
+	// may lag behind it, so the result is an estimate. It fails with ErrNotSharded unless connected through mongos.
+	CountOrphans(ctx context.Context, collection string) (int64, error)
+
+	// ListUsers returns the users defined on the database with their roles and authentication mechanisms. Credential
+	// material is never requested. It requires the viewUser privilege and fails with ErrNotAuthorized otherwise.
+	ListUsers(ctx context.Context) ([]UserInfo, error)
+
+	// CreateUser creates a user on the database authenticating with password and granted roles; roles without a
+	// database are granted on this one. It fails with ErrUserExists when the user is already defined and with
+	// ErrReadOnly on a read-only channel.
+	CreateUser(ctx context.Context, username, password string, roles []Role) error
+
+	// WaitForIndex polls currentOp every poll interval until no build of the named index of the collection is in
+	// progress, then checks that the index exists and fails with ErrIndexNotFound otherwise, for example when the
+	// build was aborted. It returns the context error when ctx is done first and needs the inprog privilege.
+	WaitForIndex(ctx context.Context, collection, indexName string, poll time.Duration) error
+
+	// DiffCollection compares the collection with the one of the same name on other, matching documents by keyField.
+	// Both sides are read in keyField order in batches of batchSize and merged, so memory stays bounded by the batches
+	// and the differing keys. Documents differ when their encodings differ, including in field order. Keys should be
+	// unique and of a single type per collection, and neither collection should have a collation.
+	DiffCollection(ctx context.Context, collection string, other *Database, keyField string, batchSize int) (*DiffResult, error)
+
+	// OplogWindow returns the time span between the oldest and the newest entry of the oplog, how far back
+	// point-in-time recovery and resuming change streams can reach, with second precision. It reads local.oplog.rs on
+	// the member selected by the handle read preference, so it fails with ErrNotReplicaSet on other topologies and
+	// with ErrNotAuthorized without read access to the local database.
+	OplogWindow(ctx context.Context) (time.Duration, error)
+
+	// ClearAutoHints drops the indexes cached for Config.AutoHint, so that they are looked up again, for example
+	// after indexes changed.
+	ClearAutoHints()
+
+	// ReplaceCollectionAtomic replaces the content of the collection with docs: they are inserted in batches of
+	// batchSize into a temporary collection given the secondary indexes of the target, which is then renamed over the
+	// target. Readers see either the old or the new documents, though the rename briefly locks the database and
+	// aborts cursors and change streams open on the target, and collection options such as validators are not
+	// carried over. The temporary collection is dropped on failure. Sharded collections cannot be renamed.
+	ReplaceCollectionAtomic(ctx context.Context, collection string, docs []any, batchSize int) error
+
+	// TopStats runs the top command and returns, per namespace, the time spent and the number of operations in
+	// total, holding read locks and holding write locks, to spot the hottest collections. The counters cover the
+	// mongod answering the command since it started, so they must be sampled on every member of interest and diffed
+	// between samples. It fails with ErrMongos when connected through mongos.
+	TopStats(ctx context.Context) (map[string]CollectionTiming, error)
+
+	// SetChangeStreamPreAndPostImages enables or disables recording the pre- and post-images of the documents of the
+	// collection, which change streams need to return fullDocumentBeforeChange and fullDocument with the required or
+	// whenAvailable options. The images are kept in config.system.preimages and expire with the oplog unless the
+	// changeStreamOptions cluster parameter sets a retention. It requires MongoDB 6.0 or later and fails with
+	// ErrUnsupportedVersion otherwise, and with ErrReadOnly on a read-only channel.
+	SetChangeStreamPreAndPostImages(ctx context.Context, collection string, enabled bool) error
+
 	// ReadConcern returns the read concern used to configure the Database object.
 	ReadConcern() *readconcern.ReadConcern
 
@@ -180,37 +456,151 @@ type MongoDB interface {
 	// WriteConcern returns the write concern used to configure the Database object.
 	WriteConcern() *writeconcern.WriteConcern
 
+	// CountMatching counts the documents matching filter in the collection, stopping after limit+1 matches. It returns
+	// the count capped at limit and whether the limit was exceeded, so a zero limit reports whether any document
+	// matches. A negative limit fails with ErrNegativeLimit.
+	CountMatching(ctx context.Context, collection string, filter any, limit int64) (int64, bool, error)
+
+	// Tail opens a tailable-await cursor on a capped collection and invokes handler for every document matching filter
+	// until the context is cancelled or handler returns an error. Invalidated cursors are reopened after the last
+	// handled document, matching documents whose resume field, _id unless set with ResumeField, is greater than its
+	// one. So the field must strictly increase in insertion order: ObjectIDs generated by several writers within the
+	// same second, or arbitrary custom ids, do not, and the documents they misorder are skipped after a reopen. The
+	// bson.Raw passed to handler is only valid until handler returns.
+	Tail(ctx context.Context, collection string, filter any, handler func(bson.Raw) error, opts ...TailOption) error
+
 	Ping(ctx context.Context) error
 
+	// EffectiveOptions returns the main settings the client actually uses, whether they come from the DSN, Config or
+	// the driver defaults, keyed like the Config fields. Settings without a Config field are keyed after their DSN
+	// option, such as socket_connect_timeout for the driver connectTimeoutMS, which is distinct from the
+	// connect_timeout bound of NewDatabase. Credentials are never included.
+	EffectiveOptions() map[string]string
+
+	// VerifyAuth checks that the connection is authenticated and that the user holds roles, including every one of
+	// Config.ExpectedRoles, without running a data operation. It returns ErrNotAuthenticated or ErrNotAuthorized.
+	VerifyAuth(ctx context.Context) error
+
+	// Invalidate marks the handle as suspect so that MongoMaker replaces it with a fresh connection the next time the
+	// channel is requested. It performs no I/O.
+	Invalidate()
+
+	// Invalidated reports whether Invalidate was called on the handle.
+	Invalidated() bool
+
 	Close(ctx context.Context) error
 }
 
 type Database struct {
 	*mongo.Database
+
+	cfg        Config
+	opts       *options.ClientOptions
+	log        *slog.Logger
+	invalid    *atomic.Bool
+	health     *healthLoop
+	budget     *retryBudget
+	transforms map[string]ReadTransform
+	hints      *hintCache
+	churn      *churnTracker
+	ttl        *ttlTracker
 }
 
-func NewDatabase(ctx context.Context, cfg Config) (*Database, error) {
-	dbName, err := ExtractDatabaseName(cfg.DSN)
+// NewDatabase connects to the database named by cfg.DatabaseName, or by the DSN path when it is empty. The ${VAR}
+// references of the DSN are replaced with the environment variables first, before the DSN is parsed or validated;
+// an unset variable fails with ErrUnresolvedDSNVar. When cfg.ConnectTimeout is set, connecting and the startup
+// checks must complete within it or fail with ErrConnectTimeout.
+func NewDatabase(ctx context.Context, cfg Config, opts ...Option) (*Database, error) {
+	var o dbOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dsn, err := expandDSN(cfg.DSN)
 	if err != nil {
 		return nil, fmt.Errorf(ErrMsgDatabase, err)
 	}
+	cfg.DSN = dsn
 
-	client, err := NewClient(ctx, cfg.DSN)
+	dbName := cfg.DatabaseName
+	if dbName == "" {
+		name, err := ExtractDatabaseName(cfg.DSN)
+		if err != nil {
+			return nil, fmt.Errorf(ErrMsgDatabase, err)
+		}
+		dbName = name
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf(ErrMsgDatabase, err)
+	}
+
+	log := newLogger(o.logger, cfg)
+
+	hooks := []ClientOptionsHook{monitorHook(log)}
+
+	var observers []func(*event.PoolEvent)
+	if cfg.SlowCheckoutThreshold > 0 {
+		observers = append(observers, newCheckoutTracker(cfg.SlowCheckoutThreshold, log).observe)
+	}
+	var churn *churnTracker
+	if cfg.Metrics {
+		churn = new(churnTracker)
+		observers = append(observers, churn.observe)
+	}
+	if len(observers) > 0 {
+		hooks = append(hooks, poolMonitorHook(observers...))
+	}
+
+	cctx, cancel := withConnectTimeout(ctx, cfg)
+	defer cancel()
+
+	client, clientOpts, err := connect(cctx, cfg, append(hooks, o.hooks...))
 	if err != nil {
-		return nil, err
+		return nil, connectTimeoutErr(ctx, cctx, cfg, err)
 	}
 
-	db := &Database{Database: client.Database(dbName)}
+	db := &Database{Database: client.Database(dbName), cfg: cfg, opts: clientOpts, log: log, invalid: new(atomic.Bool), budget: newRetryBudget(cfg.RetryBudget), transforms: o.transforms, hints: new(hintCache), churn: churn, ttl: new(ttlTracker)}
 
-	if cfg.Ping {
-		if err = db.Ping(ctx); err != nil {
-			return nil, err
-		}
+	if err = db.verify(cctx); err != nil {
+		return nil, appendErr(connectTimeoutErr(ctx, cctx, cfg, err), db.Close(ctx))
+	}
+
+	if cfg.HealthCheckInterval > 0 {
+		db.startHealthLoop(cfg.HealthCheckInterval)
 	}
 
 	return db, nil
 }
 
+// verify runs the startup checks enabled in the config.
+func (db *Database) verify(ctx context.Context) error {
+	if db.cfg.WaitForWritable {
+		if err := db.waitForWritable(ctx); err != nil {
+			return err
+		}
+	}
+
+	if db.cfg.Ping {
+		if err := db.Ping(ctx); err != nil {
+			return err
+		}
+	}
+
+	if db.cfg.RequireAuth {
+		if err := db.requireAuth(ctx); err != nil {
+			return err
+		}
+	}
+
+	if len(db.cfg.RequireCollections) > 0 {
+		if err := db.requireCollections(ctx, db.cfg.RequireCollections); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (db *Database) Ping(ctx context.Context) error {
 	if err := db.Client().Ping(ctx, readpref.Primary()); err != nil {
 		return fmt.Errorf("could not connect to MongoDB: %w", err)
@@ -218,25 +608,189 @@ func (db *Database) Ping(ctx context.Context) error {
 	return nil
 }
 
+// Invalidate is a no-op on a Database not built by NewDatabase, which MongoMaker never caches.
+func (db *Database) Invalidate() {
+	if db.invalid != nil {
+		db.invalid.Store(true)
+	}
+}
+
+func (db *Database) Invalidated() bool {
+	return db.invalid != nil && db.invalid.Load()
+}
+
+// logger returns the logger of db, slog.Default for a Database not built by NewDatabase.
+func (db *Database) logger() *slog.Logger {
+	if db.log == nil {
+		return slog.Default()
+	}
+	return db.log
+}
+
 func (db *Database) Close(ctx context.Context) error {
+	db.stopHealthLoop()
 	return db.Client().Disconnect(ctx)
 }
 
-func NewClient(ctx context.Context, uri string) (*mongo.Client, error) {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
-	if err != nil {
-		return nil, fmt.Errorf(ErrMsgClient, err)
+// ClientOptionsHook mutates the client options right before the client connects.
+type ClientOptionsHook func(o *options.ClientOptions)
+
+// NewClient connects a client for the configured DSN. The hooks run in order after the options derived from cfg are
+// applied. DNS failures while resolving a mongodb+srv:// seed list are retried up to cfg.ConnectRetries times with
+// exponential backoff, within cfg.ConnectTimeout when set.
+//
+// The options derived from cfg, such as cfg.ServerSelectionTimeout, are applied before the DSN is parsed, so an
+// option set explicitly in the DSN (serverSelectionTimeoutMS) takes precedence over the Config field; the hooks run
+// last and override both.
+func NewClient(ctx context.Context, cfg Config, hooks ...ClientOptionsHook) (*mongo.Client, error) {
+	cctx, cancel := withConnectTimeout(ctx, cfg)
+	defer cancel()
+
+	client, _, err := connect(cctx, cfg, hooks)
+	return client, connectTimeoutErr(ctx, cctx, cfg, err)
+}
+
+// withConnectTimeout bounds ctx by cfg.ConnectTimeout when it is set.
+func withConnectTimeout(ctx context.Context, cfg Config) (context.Context, context.CancelFunc) {
+	if cfg.ConnectTimeout > 0 {
+		return context.WithTimeout(ctx, cfg.ConnectTimeout)
+	}
+	return ctx, func() {}
+}
+
+// connectTimeoutErr reports err as ErrConnectTimeout when it was caused by cctx, bounded by cfg.ConnectTimeout,
+// expiring before ctx.
+func connectTimeoutErr(ctx, cctx context.Context, cfg Config, err error) error {
+	if err != nil && errors.Is(cctx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("%w after %s: %w", ErrConnectTimeout, cfg.ConnectTimeout, err)
+	}
+	return err
+}
+
+// connect implements NewClient and also returns the options the client was connected with.
+func connect(ctx context.Context, cfg Config, hooks []ClientOptionsHook) (*mongo.Client, *options.ClientOptions, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf(ErrMsgClient, err)
+	}
+
+	backoff := srvRetryBackoff
+	for attempt := 0; ; attempt++ {
+		o := clientOptions(cfg, hooks)
+
+		client, err := mongoConnect(ctx, o)
+		if err == nil {
+			return client, o, nil
+		}
+		if attempt >= cfg.ConnectRetries || !isSRVLookupError(cfg.DSN, err) {
+			return nil, nil, fmt.Errorf(ErrMsgClient, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf(ErrMsgClient, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// clientOptions builds the options for an already validated cfg. Settings from Config are applied first so that
+// options explicitly present in the DSN take precedence over them.
+func clientOptions(cfg Config, hooks []ClientOptionsHook) *options.ClientOptions {
+	o := options.Client()
+	if cfg.MaxConnIdleTime > 0 {
+		o.SetMaxConnIdleTime(cfg.MaxConnIdleTime)
 	}
-	return client, nil
+	if rp, _ := cfg.ReadPreference.ReadPref(); rp != nil {
+		o.SetReadPreference(rp)
+	}
+	if cfg.ServerMonitoringMode != "" {
+		o.SetServerMonitoringMode(cfg.ServerMonitoringMode)
+	}
+	if cfg.ServerSelectionTimeout > 0 {
+		o.SetServerSelectionTimeout(cfg.ServerSelectionTimeout)
+	}
+	if cfg.MaxPoolSize != nil {
+		o.SetMaxPoolSize(*cfg.MaxPoolSize)
+	}
+	if cfg.MinPoolSize != nil {
+		o.SetMinPoolSize(*cfg.MinPoolSize)
+	}
+	if cfg.WriteConcern != "" || cfg.WriteConcernTimeout > 0 {
+		wc := &writeconcern.WriteConcern{WTimeout: cfg.WriteConcernTimeout}
+		if cfg.WriteConcern != "" {
+			wc.W, _ = parseW(cfg.WriteConcern)
+		}
+		o.SetWriteConcern(wc)
+	}
+
+	o.ApplyURI(cfg.DSN)
+	if cred, _ := cfg.Auth.credential(); cred != nil {
+		o.SetAuth(mergeCredential(*cred, o.Auth, cfg.DSN))
+	}
+	for _, hook := range hooks {
+		if hook != nil {
+			hook(o)
+		}
+	}
+	return o
+}
+
+func isSRVLookupError(uri string, err error) bool {
+	if !strings.HasPrefix(uri, connstring.SchemeMongoDBSRV+"://") {
+		return false
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
 }
 
+// ExtractDatabaseName returns the database named by the path of the connection string. The DSN is only split, not
+// resolved, so mongodb+srv:// seed lists are not looked up and DNS failures are left to the connect retries.
 func ExtractDatabaseName(uri string) (string, error) {
-	cs, err := connstring.ParseAndValidate(uri)
-	if err != nil {
-		return "", err
+	var rest string
+	switch {
+	case strings.HasPrefix(uri, connstring.SchemeMongoDBSRV+"://"):
+		rest = uri[len(connstring.SchemeMongoDBSRV)+3:]
+	case strings.HasPrefix(uri, connstring.SchemeMongoDB+"://"):
+		rest = uri[len(connstring.SchemeMongoDB)+3:]
+	default:
+		return "", errors.New(`scheme must be "mongodb" or "mongodb+srv"`)
+	}
+
+	// the user info cannot hold an unescaped slash, so the first one starts the path
+	_, path, found := strings.Cut(rest, "/")
+	if !found {
+		return "", ErrNoDB
 	}
-	if len(cs.Database) == 0 {
+	path, _, _ = strings.Cut(path, "?")
+	if path == "" {
 		return "", ErrNoDB
 	}
-	return cs.Database, nil
+
+	name, err := url.PathUnescape(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid database %q: %w", path, err)
+	}
+	return name, nil
+}
+
+func (db *Database) WithReadPreference(rp *readpref.ReadPref) *Database {
+	return db.clone(db.Name(), options.Database().SetReadPreference(rp))
+}
+
+func (db *Database) ForDatabase(name string) *Database {
+	return db.clone(name)
+}
+
+// clone returns a handle on the name database of the same client. It inherits the read and write settings of db
+// unless opts override them.
+func (db *Database) clone(name string, opts ...*options.DatabaseOptions) *Database {
+	inherited := options.Database().
+		SetReadConcern(db.ReadConcern()).
+		SetReadPreference(db.ReadPreference()).
+		SetWriteConcern(db.WriteConcern())
+
+	c := *db
+	c.Database = db.Client().Database(name, append([]*options.DatabaseOptions{inherited}, opts...)...)
+	return &c
 }
@@ -0,0 +1,233 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// unreachableDSN names a server that is never contacted: clients connect lazily, so handles on it work for tests
+// that do no I/O.
+const unreachableDSN = "mongodb://127.0.0.1:1/app"
+
+// testDSNEnv names the environment variable holding the DSN of a disposable deployment for the integration tests.
+const testDSNEnv = "DBMONGO_TEST_DSN"
+
+// testConfig returns the config of the integration deployment, skipping tb when none is configured.
+func testConfig(tb testing.TB) Config {
+	tb.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		tb.Skipf("%s not set", testDSNEnv)
+	}
+	return Config{DSN: dsn, Ping: true}
+}
+
+// testDatabase connects to the integration deployment and drops its database when tb ends.
+func testDatabase(tb testing.TB) *Database {
+	tb.Helper()
+
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, testConfig(tb))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		_ = db.Database.Drop(ctx)
+		_ = db.Close(ctx)
+	})
+	return db
+}
+
+// lazyDatabase returns a database on a server that is never contacted, for tests failing before any I/O.
+func lazyDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, Config{DSN: unreachableDSN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(ctx)
+	})
+	return db
+}
+
+// stubConnect replaces mongoConnect with fn for the duration of t.
+func stubConnect(t *testing.T, fn func(ctx context.Context, opts ...*options.ClientOptions) (*mongo.Client, error)) {
+	t.Helper()
+
+	orig := mongoConnect
+	mongoConnect = fn
+	t.Cleanup(func() {
+		mongoConnect = orig
+	})
+}
+
+// failingSRV returns a connect func failing failures times with a DNS error before connecting lazily.
+func failingSRV(failures int, attempts *int) func(ctx context.Context, opts ...*options.ClientOptions) (*mongo.Client, error) {
+	return func(ctx context.Context, _ ...*options.ClientOptions) (*mongo.Client, error) {
+		*attempts++
+		if *attempts <= failures {
+			return nil, fmt.Errorf("error parsing uri: %w", &net.DNSError{
+				Err:         "server misbehaving",
+				Name:        "_mongodb._tcp.cluster.example.invalid",
+				IsTemporary: true,
+			})
+		}
+		return mongo.Connect(ctx, options.Client().ApplyURI(unreachableDSN))
+	}
+}
+
+func TestNewDatabaseRetriesTransientSRVFailure(t *testing.T) {
+	var attempts int
+	stubConnect(t, failingSRV(2, &attempts))
+
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, Config{DSN: "mongodb+srv://cluster.example.invalid/app", ConnectRetries: 2})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	defer db.Close(ctx)
+
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if db.Name() != "app" {
+		t.Errorf("database = %q, want app", db.Name())
+	}
+}
+
+func TestNewDatabaseGivesUpAfterConnectRetries(t *testing.T) {
+	var attempts int
+	stubConnect(t, failingSRV(5, &attempts))
+
+	_, err := NewDatabase(context.Background(), Config{DSN: "mongodb+srv://cluster.example.invalid/app", ConnectRetries: 1})
+
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		t.Fatalf("err = %v, want a DNS error", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestExtractDatabaseName(t *testing.T) {
+	tests := []struct {
+		dsn     string
+		want    string
+		wantErr bool
+	}{
+		{dsn: "mongodb://localhost:27017/app", want: "app"},
+		{dsn: "mongodb://user:p%40ss@a:1,b:2/app?replicaSet=rs0", want: "app"},
+		{dsn: "mongodb+srv://cluster.example.invalid/my%20db?retryWrites=true", want: "my db"},
+		{dsn: "mongodb://localhost:27017/c++?authSource=admin", want: "c++"},
+		{dsn: "mongodb://localhost:27017/?authSource=admin", wantErr: true},
+		{dsn: "mongodb://localhost:27017", wantErr: true},
+		{dsn: "postgres://localhost/app", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ExtractDatabaseName(tt.dsn)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ExtractDatabaseName(%q) = %q, %v; want %q, error %t", tt.dsn, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestClientOptionsMaxConnIdleTime(t *testing.T) {
+	o := clientOptions(Config{DSN: unreachableDSN, MaxConnIdleTime: 30 * time.Second}, nil)
+	if o.MaxConnIdleTime == nil || *o.MaxConnIdleTime != 30*time.Second {
+		t.Errorf("max conn idle time = %v, want 30s", o.MaxConnIdleTime)
+	}
+
+	o = clientOptions(Config{DSN: unreachableDSN + "?maxIdleTimeMS=1000", MaxConnIdleTime: 30 * time.Second}, nil)
+	if o.MaxConnIdleTime == nil || *o.MaxConnIdleTime != time.Second {
+		t.Errorf("max conn idle time = %v, want the DSN 1s", o.MaxConnIdleTime)
+	}
+}
+
+func TestMaxConnIdleTimePrunesIdleConnections(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxConnIdleTime = 200 * time.Millisecond
+
+	var (
+		mu      sync.Mutex
+		created int
+		idle    int
+	)
+	monitor := &event.PoolMonitor{Event: func(e *event.PoolEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case e.Type == event.ConnectionCreated:
+			created++
+		case e.Type == event.ConnectionClosed && e.Reason == event.ReasonIdle:
+			idle++
+		}
+	}}
+
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, cfg, WithClientOptionsHook(func(o *options.ClientOptions) {
+		o.SetPoolMonitor(monitor)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = db.Ping(ctx)
+		}()
+	}
+	wg.Wait()
+
+	// the pool closes the connections it finds idle for too long when it next checks one out
+	time.Sleep(3 * cfg.MaxConnIdleTime)
+	if err = db.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if created == 0 || idle == 0 {
+		t.Errorf("created %d connections and closed %d idle ones, want idle connections closed", created, idle)
+	}
+}
+
+func TestLiteralDatabaseIsUsable(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(unreachableDSN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+
+	db := &Database{Database: client.Database("app")}
+
+	if db.Invalidated() {
+		t.Error("literal database reported invalidated")
+	}
+	db.Invalidate()
+	db.ClearAutoHints()
+	if db.Name() != "app" || db.logger() == nil {
+		t.Errorf("database = %q, logger %v", db.Name(), db.logger())
+	}
+}
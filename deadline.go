@@ -0,0 +1,28 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (db *Database) WithDeadline(ctx context.Context, d time.Duration, fn func(context.Context) error) error {
+	dctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	err := fn(dctx)
+	if err == nil || !errors.Is(dctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if !errors.Is(err, context.DeadlineExceeded) && !mongo.IsTimeout(err) {
+		return err
+	}
+	// a parent deadline earlier than d expires both contexts, only the parent knows it fired
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("mongodb database `%s`: operation exceeded the deadline of its context, before its %s deadline: %w", db.Name(), d, err)
+	}
+	return fmt.Errorf("mongodb database `%s`: operation exceeded its %s deadline: %w", db.Name(), d, err)
+}
@@ -0,0 +1,30 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithDeadlineBlamesTheExpiredDeadline(t *testing.T) {
+	db := lazyDatabase(t)
+	wait := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	err := db.WithDeadline(context.Background(), 10*time.Millisecond, wait)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "exceeded its 10ms deadline") {
+		t.Errorf("own deadline: err = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = db.WithDeadline(ctx, time.Minute, wait)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "deadline of its context") {
+		t.Errorf("parent deadline: err = %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package dbmongo
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DiffResult struct {
+	// Missing holds the keys of the documents only found in the receiver collection.
+	Missing []bson.RawValue
+	// Extra holds the keys of the documents only found in the other collection.
+	Extra []bson.RawValue
+	// Mismatched holds the keys found in both collections whose documents differ.
+	Mismatched []bson.RawValue
+}
+
+func (db *Database) DiffCollection(ctx context.Context, collection string, other *Database, keyField string, batchSize int) (*DiffResult, error) {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	o := options.Find().SetSort(bson.D{{Key: keyField, Value: 1}}).SetBatchSize(int32(batchSize))
+
+	left, err := db.Collection(collection).Find(ctx, bson.D{}, o)
+	if err != nil {
+		return nil, err
+	}
+	defer left.Close(context.WithoutCancel(ctx))
+
+	right, err := other.Collection(collection).Find(ctx, bson.D{}, o)
+	if err != nil {
+		return nil, err
+	}
+	defer right.Close(context.WithoutCancel(ctx))
+
+	// both cursors are sorted by key, so a merge-join visits each document once
+	res := &DiffResult{}
+	hasLeft, hasRight := left.Next(ctx), right.Next(ctx)
+	for hasLeft || hasRight {
+		var lk, rk bson.RawValue
+		if hasLeft {
+			lk = left.Current.Lookup(keyField)
+		}
+		if hasRight {
+			rk = right.Current.Lookup(keyField)
+		}
+
+		c := 0
+		switch {
+		case !hasRight:
+			c = -1
+		case !hasLeft:
+			c = 1
+		default:
+			c = compareKeys(lk, rk)
+		}
+
+		switch {
+		case c < 0:
+			res.Missing = append(res.Missing, cloneValue(lk))
+			hasLeft = left.Next(ctx)
+		case c > 0:
+			res.Extra = append(res.Extra, cloneValue(rk))
+			hasRight = right.Next(ctx)
+		default:
+			if !bytes.Equal(left.Current, right.Current) {
+				res.Mismatched = append(res.Mismatched, cloneValue(lk))
+			}
+			hasLeft, hasRight = left.Next(ctx), right.Next(ctx)
+		}
+	}
+
+	if err = appendErr(left.Err(), right.Err()); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// cloneValue copies v out of the cursor buffer it points into.
+func cloneValue(v bson.RawValue) bson.RawValue {
+	return bson.RawValue{Type: v.Type, Value: slices.Clone(v.Value)}
+}
+
+// compareKeys orders a and b the way the server sorts them without collation for the common key types: by type
+// class first, then by value. Values of other types are ordered by their encoding.
+func compareKeys(a, b bson.RawValue) int {
+	if c := cmp.Compare(typeOrder(a.Type), typeOrder(b.Type)); c != 0 {
+		return c
+	}
+
+	switch a.Type {
+	case bsontype.Int32, bsontype.Int64:
+		if b.Type == bsontype.Int32 || b.Type == bsontype.Int64 {
+			return cmp.Compare(a.AsInt64(), b.AsInt64())
+		}
+		fallthrough
+	case bsontype.Double:
+		af, _ := numericValue(a)
+		bf, _ := numericValue(b)
+		return cmp.Compare(af, bf)
+	case bsontype.String, bsontype.Symbol:
+		as, _ := a.StringValueOK()
+		bs, _ := b.StringValueOK()
+		return cmp.Compare(as, bs)
+	case bsontype.DateTime:
+		return cmp.Compare(a.DateTime(), b.DateTime())
+	case bsontype.Timestamp:
+		at, ai := a.Timestamp()
+		bt, bi := b.Timestamp()
+		if c := cmp.Compare(at, bt); c != 0 {
+			return c
+		}
+		return cmp.Compare(ai, bi)
+	case bsontype.Boolean:
+		return cmp.Compare(boolOrder(a.Boolean()), boolOrder(b.Boolean()))
+	}
+	return bytes.Compare(a.Value, b.Value)
+}
+
+// typeOrder returns the rank of t in the server comparison order, numbers and strings forming a single class.
+func typeOrder(t bsontype.Type) int {
+	switch t {
+	case bsontype.MinKey:
+		return 1
+	case 0, bsontype.Null, bsontype.Undefined:
+		return 2
+	case bsontype.Int32, bsontype.Int64, bsontype.Double, bsontype.Decimal128:
+		return 3
+	case bsontype.String, bsontype.Symbol:
+		return 4
+	case bsontype.EmbeddedDocument:
+		return 5
+	case bsontype.Array:
+		return 6
+	case bsontype.Binary:
+		return 7
+	case bsontype.ObjectID:
+		return 8
+	case bsontype.Boolean:
+		return 9
+	case bsontype.DateTime:
+		return 10
+	case bsontype.Timestamp:
+		return 11
+	case bsontype.Regex:
+		return 12
+	case bsontype.MaxKey:
+		return 14
+	}
+	return 13
+}
+
+func boolOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
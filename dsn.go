@@ -0,0 +1,82 @@
+package dbmongo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrForbiddenDSNOption = errors.New("forbidden connection string option")
+	ErrUnresolvedDSNVar   = errors.New("unresolved environment variable in DSN")
+)
+
+// dsnVar matches the ${VAR} references expanded in DSNs. The bare $VAR form is left alone as it may be part of a
+// password.
+var dsnVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandDSN replaces the ${VAR} references of dsn with the values of the environment variables, failing with
+// ErrUnresolvedDSNVar listing the variables that are not set.
+func expandDSN(dsn string) (string, error) {
+	var missing []string
+	expanded := dsnVar.ReplaceAllStringFunc(dsn, func(ref string) string {
+		name := dsnVar.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnresolvedDSNVar, strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// dsnOptions returns the key=value options of the connection string query in order.
+func dsnOptions(dsn string) []string {
+	_, query, found := strings.Cut(dsn, "?")
+	if !found {
+		return nil
+	}
+	return strings.FieldsFunc(query, func(r rune) bool {
+		return r == '&' || r == ';'
+	})
+}
+
+// dsnOption returns the unescaped value of the last key option of the connection string, matched case-insensitively
+// like the driver does.
+func dsnOption(dsn, key string) (string, bool) {
+	var value string
+	var found bool
+	for _, option := range dsnOptions(dsn) {
+		k, v, _ := strings.Cut(option, "=")
+		if !strings.EqualFold(k, key) {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(v); err == nil {
+			v = unescaped
+		}
+		value, found = v, true
+	}
+	return value, found
+}
+
+// checkForbiddenOptions fails when the DSN carries one of the forbidden options. An entry names an option, matching
+// any value, or an option=value pair. Both are matched case-insensitively.
+func checkForbiddenOptions(dsn string, forbidden []string) error {
+	for _, option := range dsnOptions(dsn) {
+		key, value, _ := strings.Cut(option, "=")
+		for _, f := range forbidden {
+			fkey, fvalue, pair := strings.Cut(f, "=")
+			if !strings.EqualFold(key, fkey) || (pair && !strings.EqualFold(value, fvalue)) {
+				continue
+			}
+			return fmt.Errorf("%w: `%s`", ErrForbiddenDSNOption, f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package dbmongo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Driver defaults reported by EffectiveOptions for settings left unset.
+const (
+	defaultMaxPoolSize            = 100
+	defaultConnectTimeout         = 30 * time.Second
+	defaultServerSelectionTimeout = 30 * time.Second
+	defaultHeartbeatInterval      = 10 * time.Second
+)
+
+func (db *Database) EffectiveOptions() map[string]string {
+	o := db.opts
+	if o == nil {
+		o = options.Client()
+	}
+
+	eff := map[string]string{
+		"hosts":                    strings.Join(o.Hosts, ","),
+		"database":                 db.Name(),
+		"max_pool_size":            strconv.FormatUint(derefOr(o.MaxPoolSize, defaultMaxPoolSize), 10),
+		"min_pool_size":            strconv.FormatUint(derefOr(o.MinPoolSize, 0), 10),
+		"max_conn_idle_time":       derefOr(o.MaxConnIdleTime, 0).String(),
+		"connect_timeout":          db.cfg.ConnectTimeout.String(),
+		"socket_connect_timeout":   derefOr(o.ConnectTimeout, defaultConnectTimeout).String(),
+		"server_selection_timeout": derefOr(o.ServerSelectionTimeout, defaultServerSelectionTimeout).String(),
+		"heartbeat_interval":       derefOr(o.HeartbeatInterval, defaultHeartbeatInterval).String(),
+		"socket_timeout":           derefOr(o.SocketTimeout, 0).String(),
+		"retry_writes":             strconv.FormatBool(derefOr(o.RetryWrites, true)),
+		"retry_reads":              strconv.FormatBool(derefOr(o.RetryReads, true)),
+		"compressors":              strings.Join(o.Compressors, ","),
+		"tls":                      strconv.FormatBool(o.TLSConfig != nil),
+	}
+
+	if o.Timeout != nil {
+		eff["timeout"] = o.Timeout.String()
+	}
+	if o.ReplicaSet != nil {
+		eff["replica_set"] = *o.ReplicaSet
+	}
+	if o.AppName != nil {
+		eff["app_name"] = *o.AppName
+	}
+	if o.Auth != nil {
+		eff["auth_mechanism"] = o.Auth.AuthMechanism
+		eff["auth_source"] = o.Auth.AuthSource
+	}
+	if rc := db.ReadConcern(); rc != nil {
+		eff["read_concern"] = rc.Level
+	}
+	if rp := db.ReadPreference(); rp != nil {
+		eff["read_preference"] = rp.Mode().String()
+	}
+	if wc := db.WriteConcern(); wc != nil {
+		if w := wc.GetW(); w != nil {
+			eff["write_concern"] = fmt.Sprint(w)
+		}
+		if wc.Journal != nil {
+			eff["write_concern_journal"] = strconv.FormatBool(*wc.Journal)
+		}
+		if wc.WTimeout != 0 {
+			eff["write_concern_timeout"] = wc.WTimeout.String()
+		}
+	}
+	return eff
+}
+
+func derefOr[T any](v *T, def T) T {
+	if v == nil {
+		return def
+	}
+	return *v
+}
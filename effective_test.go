@@ -0,0 +1,24 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEffectiveOptionsConnectTimeouts(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, Config{DSN: unreachableDSN + "?connectTimeoutMS=2500", ConnectTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+
+	eff := db.EffectiveOptions()
+	if got := eff["connect_timeout"]; got != "5s" {
+		t.Errorf("connect_timeout = %q, want the Config 5s", got)
+	}
+	if got := eff["socket_connect_timeout"]; got != "2.5s" {
+		t.Errorf("socket_connect_timeout = %q, want the DSN 2.5s", got)
+	}
+}
@@ -0,0 +1,100 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	electionBackoff    = 100 * time.Millisecond
+	electionMaxBackoff = time.Second
+)
+
+// notPrimaryCodes are the server error codes returned while a replica set has no writable primary.
+var notPrimaryCodes = []int{
+	10107, // NotWritablePrimary
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+	11602, // InterruptedDueToReplStateChange
+	189,   // PrimarySteppedDown
+}
+
+// shutdownCodes are the server error codes returned by a member that is shutting down.
+var shutdownCodes = []int{
+	91,    // ShutdownInProgress
+	11600, // InterruptedAtShutdown
+}
+
+// IsNotPrimary reports whether err was caused by the absence of a writable primary, as during an election.
+func IsNotPrimary(err error) bool {
+	return hasErrorCode(err, notPrimaryCodes...)
+}
+
+// IsShutdownInProgress reports whether err was caused by the server shutting down, as during a rolling restart.
+func IsShutdownInProgress(err error) bool {
+	return hasErrorCode(err, shutdownCodes...)
+}
+
+func (db *Database) RetryOnElection(ctx context.Context, fn func(context.Context) error) error {
+	backoff := electionBackoff
+	for {
+		err := fn(ctx)
+		if err == nil || !(IsNotPrimary(err) || IsShutdownInProgress(err)) || !db.budget.allow() {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, electionMaxBackoff)
+	}
+}
+
+func (db *Database) WaitForPrimary(ctx context.Context) error {
+	backoff := electionBackoff
+	for {
+		res, err := db.hello(ctx)
+		if err == nil && res.IsWritablePrimary {
+			return nil
+		}
+		if err == nil {
+			err = ErrNoPrimary
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, electionMaxBackoff)
+	}
+}
+
+// waitForWritable waits for a writable primary within the configured WaitTimeout.
+func (db *Database) waitForWritable(ctx context.Context) error {
+	if db.cfg.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, db.cfg.WaitTimeout)
+		defer cancel()
+	}
+	return db.WaitForPrimary(ctx)
+}
+
+func hasErrorCode(err error, codes ...int) bool {
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+	for _, code := range codes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIsShutdownInProgress(t *testing.T) {
+	shutdown := mongo.CommandError{Code: 91, Name: "ShutdownInProgress", Message: "The server is in quiesce mode and will shut down"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "command error", err: shutdown, want: true},
+		{name: "wrapped", err: fmt.Errorf("insert orders: %w", shutdown), want: true},
+		{name: "wrapped twice", err: fmt.Errorf("retry: %w", fmt.Errorf("insert orders: %w", shutdown)), want: true},
+		{name: "interrupted at shutdown", err: mongo.CommandError{Code: 11600}, want: true},
+		{
+			name: "write concern error",
+			err:  mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 91, Message: "shutting down"}},
+			want: true,
+		},
+		{name: "not primary", err: mongo.CommandError{Code: 10107}},
+		{name: "plain error", err: errors.New("code 91")},
+		{name: "nil", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsShutdownInProgress(tt.err); got != tt.want {
+				t.Errorf("IsShutdownInProgress(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryOnElectionRetriesShutdown(t *testing.T) {
+	db := lazyDatabase(t)
+
+	calls := 0
+	err := db.RetryOnElection(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("insert orders: %w", mongo.CommandError{Code: 91, Name: "ShutdownInProgress"})
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("RetryOnElection = %v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	err = db.RetryOnElection(context.Background(), func(context.Context) error {
+		calls++
+		return mongo.CommandError{Code: 11000, Name: "DuplicateKey"}
+	})
+	if !hasErrorCode(err, 11000) || calls != 1 {
+		t.Errorf("RetryOnElection = %v after %d calls, want the duplicate key error at once", err, calls)
+	}
+}
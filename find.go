@@ -0,0 +1,227 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindProjected finds the documents matching filter with projection applied and decodes them into T. A projection set
+// in opts takes precedence over projection.
+func FindProjected[T any](ctx context.Context, db DB, collection string, filter, projection any, opts ...*options.FindOptions) ([]T, error) {
+	o := findOptions(db, collection, append([]*options.FindOptions{options.Find().SetProjection(projection)}, opts...))
+
+	cursor, err := find(ctx, db, collection, filter, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAll[T](ctx, cursor, readTransformFor(db, collection))
+}
+
+// find runs a find within the selection budget of ctx.
+func find(ctx context.Context, db DB, collection string, filter any, o *options.FindOptions) (cursor *mongo.Cursor, err error) {
+	err = withSelection(ctx, db, 0, func(ctx context.Context) error {
+		cursor, err = db.Collection(collection).Find(ctx, filter, o)
+		return err
+	})
+	return cursor, err
+}
+
+var ErrInvalidPage = errors.New("page and page size must be positive")
+
+type PagedResult[T any] struct {
+	Items   []T
+	Total   int64
+	Page    int64
+	HasMore bool
+}
+
+// FindPaged returns the page-th page, counting from 1, of pageSize documents matching filter in sort order, along
+// with the total number of matching documents. The count and the find run concurrently, so the total may not match
+// the items when the collection changes in between. A nil sort falls back to the channel DefaultSort; without a
+// sort the page boundaries are not stable.
+func FindPaged[T any](ctx context.Context, db DB, collection string, filter any, page, pageSize int64, sort bson.D) (PagedResult[T], error) {
+	if page < 1 || pageSize < 1 {
+		return PagedResult[T]{}, ErrInvalidPage
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	type count struct {
+		n   int64
+		err error
+	}
+	counted := make(chan count, 1)
+	go func() {
+		var n int64
+		err := withSelection(ctx, db, 0, func(ctx context.Context) (err error) {
+			n, err = db.Collection(collection).CountDocuments(ctx, filter)
+			return err
+		})
+		counted <- count{n, err}
+	}()
+
+	o := options.Find().SetSkip((page - 1) * pageSize).SetLimit(pageSize)
+	if sort != nil {
+		o.SetSort(sort)
+	}
+
+	items, err := func() ([]T, error) {
+		cursor, err := find(ctx, db, collection, filter, findOptions(db, collection, []*options.FindOptions{o}))
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeAll[T](ctx, cursor, readTransformFor(db, collection))
+	}()
+
+	c := <-counted
+	if err = appendErr(err, c.err); err != nil {
+		return PagedResult[T]{}, err
+	}
+	return PagedResult[T]{Items: items, Total: c.n, Page: page, HasMore: page*pageSize < c.n}, nil
+}
+
+// ObjectIDFilter returns an _id filter for the ObjectID in hex form id. Unlike filtering on the string itself, which
+// silently matches nothing, an id that is not a valid ObjectID fails with ErrNotObjectID.
+func ObjectIDFilter(id string) (bson.M, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotObjectID, id)
+	}
+	return bson.M{"_id": oid}, nil
+}
+
+var ErrIncomparableID = errors.New("id is not comparable")
+
+// FindByIDs fetches the documents whose _id is in ids with a single query and returns them keyed by the matching
+// element of ids. Ids without a document are absent from the map. The ids must be comparable values such as
+// ObjectIDs, strings or numbers, and are matched by their BSON type and value, except that numbers match across
+// int32, int64 and double as they do in $in; documents (bson.D, bson.M), slices and
+// other incomparable ids fail with ErrIncomparableID before any query is sent.
+func FindByIDs[T any](ctx context.Context, db DB, collection string, ids []any, opts ...*options.FindOptions) (map[any]T, error) {
+	out := make(map[any]T, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	byKey := make(map[string]any, len(ids))
+	for _, id := range ids {
+		if id != nil && !reflect.ValueOf(id).Comparable() {
+			return nil, fmt.Errorf("%w: %T", ErrIncomparableID, id)
+		}
+		t, data, err := bson.MarshalValue(id)
+		if err != nil {
+			return nil, err
+		}
+		byKey[idKey(bson.RawValue{Type: t, Value: data})] = id
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+
+	cursor, err := find(ctx, db, collection, filter, findOptions(db, collection, opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	transform := readTransformFor(db, collection)
+	for cursor.Next(ctx) {
+		raw := cursor.Current.Lookup("_id")
+
+		id, ok := byKey[idKey(raw)]
+		if !ok {
+			continue
+		}
+
+		var v T
+		if err = decodeCurrent(cursor, transform, &v); err != nil {
+			return nil, err
+		}
+		out[id] = v
+	}
+	if err = cursor.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// idKey returns the key matching an _id to the requested ids. Numbers get one key per value whatever their BSON type,
+// since $in matches 1, int64(1) and 1.0 alike; other values are keyed by their type and encoding.
+func idKey(v bson.RawValue) string {
+	switch v.Type {
+	case bsontype.Int32:
+		return "n" + strconv.FormatInt(int64(v.Int32()), 10)
+	case bsontype.Int64:
+		return "n" + strconv.FormatInt(v.Int64(), 10)
+	case bsontype.Double:
+		if f := v.Double(); f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return "n" + strconv.FormatInt(int64(f), 10)
+		}
+		return "n" + strconv.FormatFloat(v.Double(), 'g', -1, 64)
+	}
+	return string(v.Type) + string(v.Value)
+}
+
+// findDefaulter is implemented by databases carrying channel defaults for the find helpers.
+type findDefaulter interface {
+	findDefaults(collection string, o *options.FindOptions)
+}
+
+// findOptions merges opts and completes them with the channel defaults when db carries any.
+func findOptions(db DB, collection string, opts []*options.FindOptions) *options.FindOptions {
+	o := options.MergeFindOptions(opts...)
+	if d, ok := db.(findDefaulter); ok {
+		d.findDefaults(collection, o)
+	}
+	return o
+}
+
+// findOneOptions merges opts and completes them with the channel defaults of findOptions when db carries any.
+func findOneOptions(db DB, collection string, opts []*options.FindOneOptions) *options.FindOneOptions {
+	o := options.MergeFindOneOptions(opts...)
+	if d, ok := db.(findDefaulter); ok {
+		f := &options.FindOptions{Sort: o.Sort, Hint: o.Hint, Projection: o.Projection}
+		d.findDefaults(collection, f)
+		o.Sort, o.Hint, o.Projection = f.Sort, f.Hint, f.Projection
+	}
+	return o
+}
+
+// WithHint returns find options forcing the query planner to use hint, an index name or key specification. Passed
+// to the find helpers it overrides the channel DefaultHints.
+func WithHint(hint any) *options.FindOptions {
+	return options.Find().SetHint(hint)
+}
+
+func (db *Database) findDefaults(collection string, o *options.FindOptions) {
+	if o.Sort == nil && len(db.cfg.DefaultSort) > 0 {
+		o.SetSort(db.cfg.DefaultSort)
+	}
+	if hint, ok := db.cfg.DefaultHints[collection]; ok && o.Hint == nil {
+		o.SetHint(hint)
+	}
+	if db.cfg.AutoHint && o.Hint == nil && o.Projection == nil {
+		if hint := db.autoHint(collection); hint != "" {
+			o.SetHint(hint)
+		}
+	}
+	if fields := db.cfg.DefaultExcludeFields[collection]; len(fields) > 0 && o.Projection == nil {
+		projection := make(bson.D, 0, len(fields))
+		for _, field := range fields {
+			projection = append(projection, bson.E{Key: field, Value: 0})
+		}
+		o.SetProjection(projection)
+	}
+}
@@ -0,0 +1,96 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFindByIDsRejectsIncomparableIDs(t *testing.T) {
+	db := lazyDatabase(t)
+
+	tests := []struct {
+		name string
+		id   any
+	}{
+		{name: "bson.D", id: bson.D{{Key: "tenant", Value: 1}}},
+		{name: "bson.M", id: bson.M{"tenant": 1}},
+		{name: "bson.A", id: bson.A{1, 2}},
+		{name: "slice", id: []string{"a"}},
+		{name: "array of documents", id: [1]bson.D{{{Key: "tenant", Value: 1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FindByIDs[bson.M](context.Background(), db, "users", []any{"ok", tt.id})
+			if !errors.Is(err, ErrIncomparableID) {
+				t.Errorf("err = %v, want ErrIncomparableID", err)
+			}
+		})
+	}
+}
+
+func TestIDKeyNormalizesNumbers(t *testing.T) {
+	key := func(v any) string {
+		t.Helper()
+
+		typ, data, err := bson.MarshalValue(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return idKey(bson.RawValue{Type: typ, Value: data})
+	}
+
+	same := [][]any{
+		{7, int32(7), int64(7), 7.0},
+		{-1, int64(-1), -1.0},
+		{int64(1) << 40, float64(int64(1) << 40)},
+		{"7", "7"},
+	}
+	for _, ids := range same {
+		for _, id := range ids[1:] {
+			if key(id) != key(ids[0]) {
+				t.Errorf("%v (%T) and %v (%T) have different keys", id, id, ids[0], ids[0])
+			}
+		}
+	}
+
+	different := [][2]any{{7, 7.5}, {7, "7"}, {int64(1<<53 + 1), int64(1 << 53)}, {0, false}}
+	for _, ids := range different {
+		if key(ids[0]) == key(ids[1]) {
+			t.Errorf("%v (%T) and %v (%T) share a key", ids[0], ids[0], ids[1], ids[1])
+		}
+	}
+}
+
+func TestFindByIDsMixedNumericTypes(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	docs := []any{
+		bson.D{{Key: "_id", Value: int32(1)}, {Key: "name", Value: "int32"}},
+		bson.D{{Key: "_id", Value: int64(2)}, {Key: "name", Value: "int64"}},
+		bson.D{{Key: "_id", Value: 3.0}, {Key: "name", Value: "double"}},
+	}
+	if _, err := db.Collection("numeric_ids").InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []any{int64(1), 2, int32(3), 4}
+	found, err := FindByIDs[bson.M](ctx, db, "numeric_ids", ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[any]string{int64(1): "int32", 2: "int64", int32(3): "double"}
+	if len(found) != len(want) {
+		t.Errorf("found %d documents, want %d: %v", len(found), len(want), found)
+	}
+	for id, name := range want {
+		if doc, ok := found[id]; !ok || doc["name"] != name {
+			t.Errorf("id %v (%T): got %v, want the %s document", id, id, doc, name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dbmongo
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// healthFailures is the number of consecutive failed pings after which the health loop invalidates the handle.
+const healthFailures = 3
+
+// healthLoop pings a database at a fixed interval until stopped.
+type healthLoop struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// startHealthLoop pings db every interval and logs when it becomes unreachable or recovers. The driver re-dials the
+// servers on its own, but a client can stay wedged, for example on stale DNS, so after healthFailures consecutive
+// failures db is invalidated and the maker rebuilds it.
+func (db *Database) startHealthLoop(interval time.Duration) {
+	h := &healthLoop{stop: make(chan struct{}), done: make(chan struct{})}
+	db.health = h
+
+	go func() {
+		defer close(h.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		failures := 0
+		for {
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := db.Ping(ctx)
+			cancel()
+
+			switch {
+			case err == nil:
+				if failures > 0 {
+					db.logger().LogAttrs(context.Background(), slog.LevelInfo, "mongodb database reachable again", slog.String("database", db.Name()))
+				}
+				failures = 0
+				continue
+			case failures == 0:
+				db.logger().LogAttrs(context.Background(), slog.LevelError, "mongodb database unreachable", slog.String("database", db.Name()), slog.Any("error", err))
+			}
+
+			failures++
+			if failures == healthFailures {
+				db.logger().LogAttrs(context.Background(), slog.LevelWarn, "invalidating unreachable mongodb database", slog.String("database", db.Name()), slog.Int("failures", failures))
+				db.Invalidate()
+			}
+		}
+	}()
+}
+
+// stopHealthLoop stops the health loop, if any, and waits for it to exit.
+func (db *Database) stopHealthLoop() {
+	if db.health == nil {
+		return
+	}
+	db.health.once.Do(func() {
+		close(db.health.stop)
+	})
+	<-db.health.done
+}
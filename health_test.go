@@ -0,0 +1,36 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHealthLoopInvalidatesAfterConsecutiveFailures(t *testing.T) {
+	g := NewMaker(Channels{"app": {DSN: unreachableDSN, HealthCheckInterval: 20 * time.Millisecond}})
+	t.Cleanup(func() {
+		_ = g.Close(context.Background())
+	})
+
+	ctx := context.Background()
+	db, err := g.MakeMongoDB(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !db.Invalidated() {
+		if time.Now().After(deadline) {
+			t.Fatal("unreachable database not invalidated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	fresh, err := g.MakeMongoDB(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh == db {
+		t.Error("invalidated database not replaced")
+	}
+}
@@ -0,0 +1,293 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrIndexNotFound = errors.New("index not found")
+
+// defaultIndexPoll is the interval WaitForIndex polls at when no positive interval is given.
+const defaultIndexPoll = time.Second
+
+type IndexStat struct {
+	Name     string
+	Host     string
+	Accesses int64
+	Since    time.Time
+}
+
+func (db *Database) IndexUsage(ctx context.Context, collection string) ([]IndexStat, error) {
+	cursor, err := db.Collection(collection).Aggregate(ctx, mongo.Pipeline{{{Key: "$indexStats", Value: bson.D{}}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []struct {
+		Name     string `bson:"name"`
+		Host     string `bson:"host"`
+		Accesses struct {
+			Ops   int64     `bson:"ops"`
+			Since time.Time `bson:"since"`
+		} `bson:"accesses"`
+	}
+	if err = cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+
+	usage := make([]IndexStat, 0, len(stats))
+	for _, s := range stats {
+		usage = append(usage, IndexStat{Name: s.Name, Host: s.Host, Accesses: s.Accesses.Ops, Since: s.Accesses.Since})
+	}
+	return usage, nil
+}
+
+// indexConflictCodes are returned when an index being created collides with an existing one.
+var indexConflictCodes = []int{
+	68, // IndexAlreadyExists
+	85, // IndexOptionsConflict
+	86, // IndexKeySpecsConflict
+}
+
+func (db *Database) EnsureIndexes(ctx context.Context, collection string, models []mongo.IndexModel) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+
+	indexes := db.Collection(collection).Indexes()
+
+	_, err := indexes.CreateMany(ctx, models)
+	if err == nil || !hasErrorCode(err, indexConflictCodes...) {
+		return err
+	}
+
+	// another instance may have created the same indexes concurrently, which is only a conflict when an existing
+	// index differs from the requested one
+	cursor, err1 := indexes.List(ctx)
+	if err1 != nil {
+		return appendErr(err, err1)
+	}
+
+	var existing []indexSpec
+	if err1 = cursor.All(ctx, &existing); err1 != nil {
+		return appendErr(err, err1)
+	}
+
+	for _, model := range models {
+		same, err1 := existingIndex(existing, model)
+		if err1 != nil {
+			return appendErr(err, err1)
+		}
+		if !same {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexSpec is an index as listed by listIndexes, holding the options EnsureIndexes compares.
+type indexSpec struct {
+	Name                    string        `bson:"name"`
+	Key                     bson.Raw      `bson:"key"`
+	Unique                  bool          `bson:"unique"`
+	Sparse                  bool          `bson:"sparse"`
+	PartialFilterExpression bson.Raw      `bson:"partialFilterExpression"`
+	ExpireAfterSeconds      bson.RawValue `bson:"expireAfterSeconds"`
+	Collation               bson.Raw      `bson:"collation"`
+}
+
+// existingIndex reports whether existing holds an index with the name, keys and options of model.
+func existingIndex(existing []indexSpec, model mongo.IndexModel) (bool, error) {
+	keys, err := bson.Marshal(model.Keys)
+	if err != nil {
+		return false, err
+	}
+	name, err := indexName(keys, model.Options)
+	if err != nil {
+		return false, err
+	}
+
+	for _, index := range existing {
+		if index.Name == name {
+			return sameIndexKeys(index.Key, keys) && sameIndexOptions(index, model.Options), nil
+		}
+	}
+	return false, nil
+}
+
+// indexName returns the name of the index, generating the default name the driver gives unnamed indexes, such as
+// email_1 for {email: 1}.
+func indexName(keys bson.Raw, opts *options.IndexOptions) (string, error) {
+	if opts != nil && opts.Name != nil {
+		return *opts.Name, nil
+	}
+
+	elems, err := keys.Elements()
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		v := elem.Value()
+		switch v.Type {
+		case bsontype.Int32:
+			parts = append(parts, fmt.Sprintf("%s_%d", elem.Key(), v.Int32()))
+		case bsontype.Int64:
+			parts = append(parts, fmt.Sprintf("%s_%d", elem.Key(), v.Int64()))
+		case bsontype.String:
+			parts = append(parts, elem.Key()+"_"+v.StringValue())
+		default:
+			return "", mongo.ErrInvalidIndexValue
+		}
+	}
+	return strings.Join(parts, "_"), nil
+}
+
+// sameIndexOptions reports whether index was created with opts. An index without a requested collation inherits the
+// collection default, so the collation is only compared when one is requested, and then only the fields set in it
+// since the server fills in the rest.
+func sameIndexOptions(index indexSpec, opts *options.IndexOptions) bool {
+	if opts == nil {
+		opts = options.Index()
+	}
+
+	if index.Unique != (opts.Unique != nil && *opts.Unique) || index.Sparse != (opts.Sparse != nil && *opts.Sparse) {
+		return false
+	}
+
+	ttl, ok := numericValue(index.ExpireAfterSeconds)
+	switch {
+	case opts.ExpireAfterSeconds == nil:
+		if ok {
+			return false
+		}
+	case !ok || ttl != float64(*opts.ExpireAfterSeconds):
+		return false
+	}
+
+	if opts.PartialFilterExpression == nil {
+		if index.PartialFilterExpression != nil {
+			return false
+		}
+	} else {
+		filter, err := bson.Marshal(opts.PartialFilterExpression)
+		if err != nil || !sameIndexKeys(index.PartialFilterExpression, filter) {
+			return false
+		}
+	}
+
+	if opts.Collation != nil {
+		elems, err := opts.Collation.ToDocument().Elements()
+		if err != nil {
+			return false
+		}
+		for _, elem := range elems {
+			v, err := index.Collation.LookupErr(elem.Key())
+			if err != nil || !sameValue(v, elem.Value()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sameIndexKeys compares documents field by field, treating numbers of different BSON types, such as index
+// directions or partial filter operands, as equal.
+func sameIndexKeys(a, b bson.Raw) bool {
+	ea, err := a.Elements()
+	if err != nil {
+		return false
+	}
+	eb, err := b.Elements()
+	if err != nil || len(ea) != len(eb) {
+		return false
+	}
+
+	for i := range ea {
+		if ea[i].Key() != eb[i].Key() || !sameValue(ea[i].Value(), eb[i].Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameValue(a, b bson.RawValue) bool {
+	fa, oka := numericValue(a)
+	fb, okb := numericValue(b)
+	switch {
+	case oka && okb:
+		return fa == fb
+	case a.Type == bsontype.EmbeddedDocument && b.Type == bsontype.EmbeddedDocument:
+		return sameIndexKeys(a.Document(), b.Document())
+	case a.Type == bsontype.Array && b.Type == bsontype.Array:
+		return sameIndexKeys(bson.Raw(a.Array()), bson.Raw(b.Array()))
+	default:
+		return a.Equal(b)
+	}
+}
+
+func numericValue(v bson.RawValue) (float64, bool) {
+	if i, ok := v.Int32OK(); ok {
+		return float64(i), true
+	}
+	if i, ok := v.Int64OK(); ok {
+		return float64(i), true
+	}
+	return v.DoubleOK()
+}
+
+func (db *Database) WaitForIndex(ctx context.Context, collection, indexName string, poll time.Duration) error {
+	if poll <= 0 {
+		poll = defaultIndexPoll
+	}
+
+	for {
+		building, err := db.indexBuilding(ctx, collection, indexName)
+		if err != nil {
+			return err
+		}
+		if !building {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+
+	specs, err := db.Collection(collection).Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.Name == indexName {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s on %s", ErrIndexNotFound, indexName, collection)
+}
+
+// indexBuilding reports whether currentOp lists a build of the named index of the collection in progress.
+func (db *Database) indexBuilding(ctx context.Context, collection, indexName string) (bool, error) {
+	var res struct {
+		InProg []bson.Raw `bson:"inprog"`
+	}
+	err := db.admin().RunCommand(ctx, bson.D{
+		{Key: "currentOp", Value: true},
+		{Key: "command.createIndexes", Value: collection},
+		{Key: "command.indexes.name", Value: indexName},
+		{Key: "ns", Value: db.Name() + "." + collection},
+	}).Decode(&res)
+	return len(res.InProg) > 0, err
+}
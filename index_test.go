@@ -0,0 +1,172 @@
+package dbmongo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+func TestIndexName(t *testing.T) {
+	tests := []struct {
+		keys bson.D
+		opts *options.IndexOptions
+		want string
+	}{
+		{keys: bson.D{{Key: "email", Value: 1}}, want: "email_1"},
+		{keys: bson.D{{Key: "a", Value: 1}, {Key: "b", Value: int64(-1)}}, want: "a_1_b_-1"},
+		{keys: bson.D{{Key: "body", Value: "text"}}, want: "body_text"},
+		{keys: bson.D{{Key: "email", Value: 1}}, opts: options.Index().SetName("by_email"), want: "by_email"},
+	}
+
+	for _, tt := range tests {
+		keys, err := bson.Marshal(tt.keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := indexName(keys, tt.opts)
+		if err != nil || got != tt.want {
+			t.Errorf("indexName(%v) = %q, %v; want %q", tt.keys, got, err, tt.want)
+		}
+	}
+}
+
+func TestExistingIndex(t *testing.T) {
+	email := mustMarshal(t, bson.D{{Key: "email", Value: int32(1)}})
+	filter := mustMarshal(t, bson.D{{Key: "deleted", Value: bson.D{{Key: "$eq", Value: false}}}})
+	collation := mustMarshal(t, bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int32(2)}, {Key: "caseLevel", Value: false}})
+	ttl := bson.RawValue{Type: bson.TypeInt64, Value: bsoncore.AppendInt64(nil, 3600)}
+
+	existing := []indexSpec{
+		{Name: "_id_", Key: mustMarshal(t, bson.D{{Key: "_id", Value: int32(1)}})},
+		{Name: "email_1", Key: email, Unique: true, PartialFilterExpression: filter, Collation: collation},
+		{Name: "expires", Key: mustMarshal(t, bson.D{{Key: "at", Value: 1.0}}), ExpireAfterSeconds: ttl},
+	}
+
+	emailKeys := bson.D{{Key: "email", Value: 1}}
+	partial := bson.D{{Key: "deleted", Value: bson.D{{Key: "$eq", Value: false}}}}
+	tests := []struct {
+		name  string
+		model mongo.IndexModel
+		want  bool
+	}{
+		{
+			name: "same",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(partial).SetCollation(&options.Collation{Locale: "en", Strength: 2})},
+			want: true,
+		},
+		{
+			name:  "collation omitted",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetUnique(true).SetPartialFilterExpression(partial)},
+			want:  true,
+		},
+		{
+			name:  "not unique",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetPartialFilterExpression(partial)},
+		},
+		{
+			name: "sparse",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetUnique(true).SetSparse(true).
+				SetPartialFilterExpression(partial)},
+		},
+		{
+			name:  "no partial filter",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetUnique(true)},
+		},
+		{
+			name: "other partial filter",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "deleted", Value: true}})},
+		},
+		{
+			name: "other collation",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(partial).SetCollation(&options.Collation{Locale: "fr"})},
+		},
+		{
+			name:  "other name",
+			model: mongo.IndexModel{Keys: emailKeys, Options: options.Index().SetName("by_email").SetUnique(true)},
+		},
+		{
+			name:  "unnamed does not match any name",
+			model: mongo.IndexModel{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(3600)},
+		},
+		{
+			name:  "ttl",
+			model: mongo.IndexModel{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetName("expires").SetExpireAfterSeconds(3600)},
+			want:  true,
+		},
+		{
+			name:  "other ttl",
+			model: mongo.IndexModel{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetName("expires").SetExpireAfterSeconds(60)},
+		},
+		{
+			name:  "no ttl",
+			model: mongo.IndexModel{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetName("expires")},
+		},
+		{
+			name:  "other keys",
+			model: mongo.IndexModel{Keys: bson.D{{Key: "at", Value: -1}}, Options: options.Index().SetName("expires").SetExpireAfterSeconds(3600)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := existingIndex(existing, tt.model)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("existingIndex = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureIndexesConcurrently(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetName("expires").SetExpireAfterSeconds(3600)},
+	}
+
+	const workers = 8
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = db.EnsureIndexes(ctx, "ensure_indexes", models)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("worker %d: %v", i, err)
+		}
+	}
+
+	conflicting := []mongo.IndexModel{{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetName("expires").SetExpireAfterSeconds(60)}}
+	if err := db.EnsureIndexes(ctx, "ensure_indexes", conflicting); !hasErrorCode(err, indexConflictCodes...) {
+		t.Errorf("conflicting TTL: err = %v, want an index conflict", err)
+	}
+}
+
+func mustMarshal(t *testing.T, v any) bson.Raw {
+	t.Helper()
+
+	b, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
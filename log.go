@@ -0,0 +1,130 @@
+package dbmongo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	LogSilent = "silent"
+	LogError  = "error"
+	LogInfo   = "info"
+	LogDebug  = "debug"
+)
+
+// levelSilent is above every level, so nothing is logged.
+const levelSilent = slog.Level(math.MaxInt)
+
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "", LogError:
+		return slog.LevelError, nil
+	case LogSilent:
+		return levelSilent, nil
+	case LogInfo:
+		return slog.LevelInfo, nil
+	case LogDebug:
+		return slog.LevelDebug, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown log_level `%s`", ErrInvalidConfig, level)
+	}
+}
+
+// levelHandler drops the records below the channel log level before they reach the wrapped handler.
+type levelHandler struct {
+	slog.Handler
+
+	level slog.Level
+}
+
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.Handler.Enabled(ctx, level)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// newLogger gates base by the level of an already validated cfg.
+func newLogger(base *slog.Logger, cfg Config) *slog.Logger {
+	if base == nil {
+		base = slog.Default()
+	}
+	level, _ := parseLogLevel(cfg.LogLevel)
+	return slog.New(levelHandler{Handler: base.Handler(), level: level})
+}
+
+// monitorHook installs the monitors logging the commands and, at debug level, the server handshakes of the client.
+func monitorHook(log *slog.Logger) ClientOptionsHook {
+	return func(o *options.ClientOptions) {
+		ctx := context.Background()
+		if !log.Enabled(ctx, slog.LevelError) {
+			return
+		}
+
+		if log.Enabled(ctx, slog.LevelDebug) {
+			o.SetServerMonitor(&event.ServerMonitor{
+				ServerDescriptionChanged: func(e *event.ServerDescriptionChangedEvent) {
+					logCompression(log, e)
+				},
+			})
+		}
+
+		o.SetMonitor(&event.CommandMonitor{
+			Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+				log.LogAttrs(ctx, slog.LevelDebug, "mongodb command started",
+					slog.String("command", e.CommandName),
+					slog.String("database", e.DatabaseName),
+					slog.Int64("request_id", e.RequestID),
+					slog.String("connection", e.ConnectionID),
+				)
+			},
+			Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+				log.LogAttrs(ctx, slog.LevelDebug, "mongodb command succeeded",
+					slog.String("command", e.CommandName),
+					slog.Int64("request_id", e.RequestID),
+					slog.Duration("duration", e.Duration),
+				)
+			},
+			Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+				log.LogAttrs(ctx, slog.LevelError, "mongodb command failed",
+					slog.String("command", e.CommandName),
+					slog.Int64("request_id", e.RequestID),
+					slog.Duration("duration", e.Duration),
+					slog.String("failure", e.Failure),
+				)
+			},
+		})
+	}
+}
+
+// logCompression reports the compressors a server agreed on after its first handshake and whenever they change. The
+// first one is used on the wire, none means the connection is uncompressed.
+func logCompression(log *slog.Logger, e *event.ServerDescriptionChangedEvent) {
+	handshake := e.PreviousDescription.Kind == 0 && e.NewDescription.Kind != 0
+	if !handshake && slices.Equal(e.PreviousDescription.Compression, e.NewDescription.Compression) {
+		return
+	}
+
+	compressor := "none"
+	if len(e.NewDescription.Compression) > 0 {
+		compressor = e.NewDescription.Compression[0]
+	}
+
+	log.LogAttrs(context.Background(), slog.LevelDebug, "mongodb compression negotiated",
+		slog.String("address", e.Address.String()),
+		slog.String("compressor", compressor),
+		slog.Any("supported", e.NewDescription.Compression),
+	)
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
 	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var ErrConfigNotFound = errors.New("mongo config not found")
@@ -16,8 +21,11 @@ type Maker interface {
 type MongoMaker struct {
 	sync.RWMutex
 
-	channels Channels
-	db       map[string]MongoDB
+	channels    Channels
+	db          map[string]MongoDB
+	optionsHook func(name string, o *options.ClientOptions)
+	logger      *slog.Logger
+	transforms  map[string]map[string]ReadTransform
 }
 
 func NewMaker(channels Channels) *MongoMaker {
@@ -28,8 +36,9 @@ func NewMaker(channels Channels) *MongoMaker {
 }
 
 func (g *MongoMaker) MakeMongoDB(ctx context.Context, name string) (MongoDB, error) {
-	if db := g.getDB(name); db != nil {
-		return db, nil
+	stale := g.getDB(name)
+	if stale != nil && !stale.Invalidated() {
+		return stale, nil
 	}
 
 	cfg, err := g.getConfig(name)
@@ -37,35 +46,244 @@ func (g *MongoMaker) MakeMongoDB(ctx context.Context, name string) (MongoDB, err
 		return nil, err
 	}
 
-	database, err := NewDatabase(ctx, cfg)
+	database, err := NewDatabase(ctx, cfg, g.options(name)...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("channel `%s`: %w", name, err)
 	}
 
 	g.Lock()
-	defer g.Unlock()
-
+	current := g.db[name]
+	if current != nil && current != stale && !current.Invalidated() {
+		// a concurrent call connected the channel first, its handle wins
+		g.Unlock()
+		_ = database.Close(ctx)
+		return current, nil
+	}
 	g.db[name] = database
+	g.Unlock()
+
+	// only the call replacing a handle closes it, so it is closed once and never while the lock is held; the
+	// invalidated handle is suspect already, a failing disconnect has nothing left to report
+	if current != nil {
+		_ = current.Close(ctx)
+	}
 
 	return database, nil
 }
 
+// MakeEphemeral connects to dsn, which need not belong to a configured channel, for one-off queries against ad-hoc
+// clusters. The database name is taken from the DSN path and the other Config settings keep their defaults. The
+// database is not cached: callers own it and must call the returned cleanup function to disconnect it.
+func (g *MongoMaker) MakeEphemeral(ctx context.Context, dsn string) (MongoDB, func() error, error) {
+	database, err := NewDatabase(ctx, Config{DSN: dsn}, WithLogger(g.channelLogger("ephemeral")))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return database, func() error {
+		return database.Close(context.Background())
+	}, nil
+}
+
+// MakeAll creates the databases of every configured channel, for deployments that prefer connecting eagerly. The
+// databases created successfully are returned even when others fail, along with the aggregated errors.
+func (g *MongoMaker) MakeAll(ctx context.Context) (map[string]MongoDB, error) {
+	names := g.channelNames()
+
+	var err error
+	dbs := make(map[string]MongoDB, len(names))
+	for _, name := range names {
+		db, err1 := g.MakeMongoDB(ctx, name)
+		if err1 != nil {
+			err = appendErr(err, err1)
+			continue
+		}
+		dbs[name] = db
+	}
+	return dbs, err
+}
+
+// SetClientOptionsHook registers fn to adjust the driver options of every channel connected afterwards. It runs just
+// before the client connects, after the options derived from the channel Config are applied, and is the escape hatch
+// for driver settings Config does not expose. Already cached databases are not affected.
+func (g *MongoMaker) SetClientOptionsHook(fn func(name string, o *options.ClientOptions)) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.optionsHook = fn
+}
+
+// SetLogger sets the logger of the channels connected afterwards, slog.Default is used otherwise. Records carry the
+// channel name.
+func (g *MongoMaker) SetLogger(logger *slog.Logger) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.logger = logger
+}
+
+// SetReadTransform registers fn as the ReadTransform of collection on the named channel, applied by the typed read
+// helpers of the package (FindProjected, FindByIDs, FindPaged, Stream and TypedCollection reads) to every document
+// before decoding. Documents read through the collection handles directly are left untouched. The transform runs on
+// the hot path of every read, and a non-nil transform makes the helpers decode documents one by one, so it should
+// stay cheap and return its input unchanged for documents already in the current shape. A nil fn removes the
+// transform. Only channels connected afterwards are affected.
+func (g *MongoMaker) SetReadTransform(channel, collection string, fn ReadTransform) {
+	g.Lock()
+	defer g.Unlock()
+
+	if fn == nil {
+		delete(g.transforms[channel], collection)
+		return
+	}
+	if g.transforms == nil {
+		g.transforms = map[string]map[string]ReadTransform{}
+	}
+	if g.transforms[channel] == nil {
+		g.transforms[channel] = map[string]ReadTransform{}
+	}
+	g.transforms[channel][collection] = fn
+}
+
 func (g *MongoMaker) Close(ctx context.Context) (err error) {
 	g.RLock()
 	defer g.RUnlock()
 
 	for _, db := range g.db {
-		if err1 := db.Close(ctx); err1 != nil {
-			if err == nil {
-				err = err1
-			} else {
-				err = fmt.Errorf("%w; %w", err, err1)
-			}
-		}
+		err = appendErr(err, db.Close(ctx))
 	}
 	return
 }
 
+type ChannelHealth struct {
+	OK      bool
+	Latency time.Duration
+	Err     error
+}
+
+// HealthReport pings every cached database concurrently and reports the outcome and round-trip latency per channel.
+func (g *MongoMaker) HealthReport(ctx context.Context) map[string]ChannelHealth {
+	dbs := g.cached()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = make(map[string]ChannelHealth, len(dbs))
+	)
+
+	for name, db := range dbs {
+		wg.Add(1)
+		go func(name string, db MongoDB) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := db.Ping(ctx)
+			health := ChannelHealth{OK: err == nil, Latency: time.Since(start), Err: err}
+
+			mu.Lock()
+			report[name] = health
+			mu.Unlock()
+		}(name, db)
+	}
+	wg.Wait()
+
+	return report
+}
+
+type MakerStats struct {
+	// Configured is the number of channels in the config.
+	Configured int
+	// Connected is the number of channels with a cached database.
+	Connected int
+	// Names lists the connected channels in sorted order.
+	Names []string
+}
+
+func (g *MongoMaker) Stats() MakerStats {
+	g.RLock()
+	defer g.RUnlock()
+
+	names := make([]string, 0, len(g.db))
+	for name := range g.db {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return MakerStats{Configured: len(g.channels), Connected: len(g.db), Names: names}
+}
+
+// Channels returns the configured channel names in sorted order, without exposing their configuration.
+func (g *MongoMaker) Channels() []string {
+	return g.channelNames()
+}
+
+// ChurnStats returns, per connected channel with Config.Metrics enabled, the number of pooled connections created and
+// closed over the last few minutes. Sustained churn usually means connections are pruned too eagerly, for example by
+// a short MaxConnIdleTime, and recreated for the next burst.
+func (g *MongoMaker) ChurnStats() map[string]ChurnStat {
+	stats := map[string]ChurnStat{}
+	for name, db := range g.cached() {
+		if d, ok := db.(*Database); ok && d.churn != nil {
+			stats[name] = d.churn.stat()
+		}
+	}
+	return stats
+}
+
+// channelNames returns the configured channel names in sorted order.
+func (g *MongoMaker) channelNames() []string {
+	g.RLock()
+	defer g.RUnlock()
+
+	names := make([]string, 0, len(g.channels))
+	for name := range g.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (g *MongoMaker) cached() map[string]MongoDB {
+	g.RLock()
+	defer g.RUnlock()
+
+	dbs := make(map[string]MongoDB, len(g.db))
+	for name, db := range g.db {
+		dbs[name] = db
+	}
+	return dbs
+}
+
+func (g *MongoMaker) options(name string) []Option {
+	logger := g.channelLogger(name)
+
+	g.RLock()
+	defer g.RUnlock()
+
+	opts := []Option{WithLogger(logger)}
+	if fn := g.optionsHook; fn != nil {
+		opts = append(opts, WithClientOptionsHook(func(o *options.ClientOptions) {
+			fn(name, o)
+		}))
+	}
+	for collection, fn := range g.transforms[name] {
+		opts = append(opts, WithReadTransform(collection, fn))
+	}
+	return opts
+}
+
+// channelLogger returns the logger of the maker, slog.Default when none is set, with the channel attribute.
+func (g *MongoMaker) channelLogger(name string) *slog.Logger {
+	g.RLock()
+	defer g.RUnlock()
+
+	logger := g.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger.With(slog.String("channel", name))
+}
+
 func (g *MongoMaker) getDB(name string) MongoDB {
 	g.RLock()
 	defer g.RUnlock()
@@ -0,0 +1,144 @@
+package dbmongo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// closeCounter is an invalidated handle counting how often it is closed.
+type closeCounter struct {
+	*Database
+	closed atomic.Int32
+}
+
+func (c *closeCounter) Invalidated() bool {
+	return true
+}
+
+func (c *closeCounter) Close(ctx context.Context) error {
+	c.closed.Add(1)
+	return c.Database.Close(ctx)
+}
+
+// makeConcurrently calls MakeMongoDB for name from n goroutines at once.
+func makeConcurrently(t *testing.T, g *MongoMaker, name string, n int) []MongoDB {
+	t.Helper()
+
+	dbs := make([]MongoDB, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dbs[i], errs[i] = g.MakeMongoDB(context.Background(), name)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dbs
+}
+
+func TestMakeMongoDBConcurrently(t *testing.T) {
+	g := NewMaker(Channels{"app": {DSN: unreachableDSN}})
+	t.Cleanup(func() {
+		_ = g.Close(context.Background())
+	})
+
+	dbs := makeConcurrently(t, g, "app", 16)
+
+	cached := g.getDB("app")
+	for i, db := range dbs {
+		if db != cached {
+			t.Fatalf("call %d returned a handle that is not cached", i)
+		}
+	}
+}
+
+func TestMakeMongoDBReplacesInvalidatedOnce(t *testing.T) {
+	g := NewMaker(Channels{"app": {DSN: unreachableDSN}})
+	t.Cleanup(func() {
+		_ = g.Close(context.Background())
+	})
+
+	db, err := NewDatabase(context.Background(), Config{DSN: unreachableDSN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := &closeCounter{Database: db}
+	g.db["app"] = stale
+
+	dbs := makeConcurrently(t, g, "app", 16)
+
+	cached := g.getDB("app")
+	if cached == MongoDB(stale) {
+		t.Fatal("invalidated handle still cached")
+	}
+	for i, db := range dbs {
+		if db != cached {
+			t.Fatalf("call %d returned a handle that is not cached", i)
+		}
+	}
+	if n := stale.closed.Load(); n != 1 {
+		t.Errorf("invalidated handle closed %d times, want 1", n)
+	}
+}
+
+func TestPoolSizesReachClientOptions(t *testing.T) {
+	maxSize, minSize := uint64(50), uint64(5)
+	g := NewMaker(Channels{
+		"config": {DSN: unreachableDSN, MaxPoolSize: &maxSize, MinPoolSize: &minSize},
+		"dsn":    {DSN: unreachableDSN + "?maxPoolSize=20&minPoolSize=2", MaxPoolSize: &maxSize, MinPoolSize: &minSize},
+		"unset":  {DSN: unreachableDSN},
+	})
+	t.Cleanup(func() {
+		_ = g.Close(context.Background())
+	})
+
+	var mu sync.Mutex
+	seen := map[string]*options.ClientOptions{}
+	g.SetClientOptionsHook(func(name string, o *options.ClientOptions) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		seen[name] = o
+	})
+
+	if _, err := g.MakeAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		channel  string
+		max, min uint64
+		set      bool
+	}{
+		{channel: "config", max: 50, min: 5, set: true},
+		{channel: "dsn", max: 20, min: 2, set: true},
+		{channel: "unset"},
+	}
+	for _, tt := range tests {
+		o := seen[tt.channel]
+		if o == nil {
+			t.Fatalf("%s: hook not called", tt.channel)
+		}
+		if !tt.set {
+			if o.MaxPoolSize != nil || o.MinPoolSize != nil {
+				t.Errorf("%s: pool sizes %v/%v set, want driver defaults", tt.channel, o.MaxPoolSize, o.MinPoolSize)
+			}
+			continue
+		}
+		if o.MaxPoolSize == nil || *o.MaxPoolSize != tt.max || o.MinPoolSize == nil || *o.MinPoolSize != tt.min {
+			t.Errorf("%s: pool sizes %v/%v, want %d/%d", tt.channel, o.MaxPoolSize, o.MinPoolSize, tt.max, tt.min)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrUnknownHost = errors.New("host is not a member of the mongodb deployment")
+
+func (db *Database) ReadFromAllMembers(ctx context.Context, collection string, filter any) (map[string]bson.Raw, error) {
+	hosts, err := db.members(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]bson.Raw, len(hosts))
+	for _, host := range hosts {
+		raw, err1 := db.readFromHost(ctx, host, collection, filter)
+		if err1 != nil {
+			err = appendErr(err, fmt.Errorf("%s: %w", host, err1))
+			continue
+		}
+		results[host] = raw
+	}
+	return results, err
+}
+
+func (db *Database) readFromHost(ctx context.Context, host, collection string, filter any) (_ bson.Raw, err error) {
+	client, err := mongo.Connect(ctx, db.directClientOptions(host))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = appendErr(err, client.Disconnect(context.WithoutCancel(ctx)))
+	}()
+
+	raw, err := client.Database(db.Name()).Collection(collection).FindOne(ctx, filter).DecodeBytes()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	return raw, err
+}
+
+func (db *Database) CollectionOnHost(ctx context.Context, host, collection string) (*mongo.Collection, func() error, error) {
+	hosts, err := db.members(ctx)
+	if errors.Is(err, ErrNotReplicaSet) {
+		hosts, err = db.opts.Hosts, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(hosts, host) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownHost, host)
+	}
+
+	client, err := mongo.Connect(ctx, db.directClientOptions(host))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client.Database(db.Name()).Collection(collection), func() error {
+		return client.Disconnect(context.Background())
+	}, nil
+}
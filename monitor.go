@@ -0,0 +1,69 @@
+package dbmongo
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// poolMonitorHook installs a pool monitor dispatching every event to the observers.
+func poolMonitorHook(observers ...func(*event.PoolEvent)) ClientOptionsHook {
+	return func(o *options.ClientOptions) {
+		o.SetPoolMonitor(&event.PoolMonitor{
+			Event: func(e *event.PoolEvent) {
+				for _, observe := range observers {
+					observe(e)
+				}
+			},
+		})
+	}
+}
+
+// checkoutTracker logs connection checkouts waiting longer than the threshold. Pool events carry no correlation to
+// the waiting operation, so checkouts are paired per address in the order they started, matching the FIFO order in
+// which the pool hands out connections.
+type checkoutTracker struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	log       *slog.Logger
+	pending   map[string][]time.Time
+}
+
+func newCheckoutTracker(threshold time.Duration, log *slog.Logger) *checkoutTracker {
+	return &checkoutTracker{threshold: threshold, log: log, pending: map[string][]time.Time{}}
+}
+
+func (t *checkoutTracker) observe(e *event.PoolEvent) {
+	switch e.Type {
+	case event.GetStarted:
+		t.mu.Lock()
+		t.pending[e.Address] = append(t.pending[e.Address], time.Now())
+		t.mu.Unlock()
+	case event.GetSucceeded, event.GetFailed:
+		t.mu.Lock()
+		queue := t.pending[e.Address]
+		if len(queue) == 0 {
+			t.mu.Unlock()
+			return
+		}
+		started := queue[0]
+		t.pending[e.Address] = queue[1:]
+		t.mu.Unlock()
+
+		if wait := time.Since(started); wait >= t.threshold {
+			t.log.LogAttrs(context.Background(), slog.LevelWarn, "mongodb slow connection checkout",
+				slog.String("address", e.Address),
+				slog.Duration("wait", wait),
+				slog.Bool("failed", e.Type == event.GetFailed),
+			)
+		}
+	case event.PoolCleared, event.PoolClosedEvent:
+		t.mu.Lock()
+		delete(t.pending, e.Address)
+		t.mu.Unlock()
+	}
+}
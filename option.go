@@ -0,0 +1,39 @@
+package dbmongo
+
+import (
+	"log/slog"
+)
+
+// Option configures how NewDatabase creates a Database.
+type Option func(o *dbOptions)
+
+type dbOptions struct {
+	hooks      []ClientOptionsHook
+	logger     *slog.Logger
+	transforms map[string]ReadTransform
+}
+
+// WithClientOptionsHook adds a hook passed on to NewClient.
+func WithClientOptionsHook(hook ClientOptionsHook) Option {
+	return func(o *dbOptions) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// WithLogger sets the logger of the database, gated by Config.LogLevel. slog.Default is used otherwise.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *dbOptions) {
+		o.logger = logger
+	}
+}
+
+// WithReadTransform sets the ReadTransform the typed read helpers of the package apply to the documents of collection
+// before decoding them.
+func WithReadTransform(collection string, fn ReadTransform) Option {
+	return func(o *dbOptions) {
+		if o.transforms == nil {
+			o.transforms = map[string]ReadTransform{}
+		}
+		o.transforms[collection] = fn
+	}
+}
@@ -2,6 +2,9 @@ package dbmongo
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/roadrunner-server/endure/v2/dep"
 	"github.com/roadrunner-server/errors"
@@ -9,8 +12,19 @@ import (
 
 const PluginName = "db.mongo"
 
+const (
+	// monitorInterval is how often Serve pings the connected channels.
+	monitorInterval = 10 * time.Second
+	// unreachableThreshold is how long a channel may fail its pings before Serve reports it.
+	unreachableThreshold = time.Minute
+)
+
 type Plugin struct {
 	maker *MongoMaker
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
 }
 
 func (p *Plugin) Init(cfg Configurer) error {
@@ -31,13 +45,118 @@ func (p *Plugin) Init(cfg Configurer) error {
 }
 
 func (p *Plugin) Serve() chan error {
-	return make(chan error, 1)
+	errCh := make(chan error, 1)
+
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+
+	go p.run(errCh)
+
+	return errCh
 }
 
 func (p *Plugin) Stop(ctx context.Context) error {
+	if p.stop != nil {
+		// the container may stop the plugin more than once, for example after a failed Serve
+		p.stopOnce.Do(func() {
+			close(p.stop)
+		})
+
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+		}
+	}
+
 	return p.maker.Close(ctx)
 }
 
+// run connects the eager channels, then monitors the connected ones until Stop.
+func (p *Plugin) run(errCh chan<- error) {
+	defer close(p.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-p.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := p.warmUp(ctx); err != nil {
+		errCh <- err
+		return
+	}
+	p.monitor(errCh)
+}
+
+// warmUp connects the channels configured as Eager. A failing Optional channel is only logged and left to connect on
+// first use; any other failure is returned.
+func (p *Plugin) warmUp(ctx context.Context) error {
+	const op = errors.Op("db.mongo_plugin_serve")
+
+	for _, name := range p.maker.Channels() {
+		cfg, err := p.maker.getConfig(name)
+		if err != nil || !cfg.Eager {
+			continue
+		}
+
+		if _, err = p.maker.MakeMongoDB(ctx, name); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if cfg.Optional {
+				p.maker.channelLogger(name).Warn("optional mongo channel unavailable, connecting on first use", "error", err)
+				continue
+			}
+			return errors.E(op, err)
+		}
+	}
+	return nil
+}
+
+// monitor pings the connected channels and reports a fatal error once one of them stays unreachable for longer than
+// unreachableThreshold.
+func (p *Plugin) monitor(errCh chan<- error) {
+	const op = errors.Op("db.mongo_plugin_serve")
+
+	ticker := time.NewTicker(monitorInterval)
+	defer ticker.Stop()
+
+	failing := map[string]time.Time{}
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), monitorInterval)
+		report := p.maker.HealthReport(ctx)
+		cancel()
+
+		now := time.Now()
+		for name, health := range report {
+			if health.OK {
+				delete(failing, name)
+				continue
+			}
+
+			since, ok := failing[name]
+			if !ok {
+				failing[name] = now
+				continue
+			}
+			if now.Sub(since) >= unreachableThreshold {
+				errCh <- errors.E(op, fmt.Errorf("mongo channel `%s` unreachable for %s: %w", name, now.Sub(since).Round(time.Second), health.Err))
+				return
+			}
+		}
+	}
+}
+
 func (p *Plugin) Provides() []*dep.Out {
 	return []*dep.Out{
 		dep.Bind((*Maker)(nil), p.MongoMaker),
@@ -48,6 +167,15 @@ func (p *Plugin) MongoMaker() *MongoMaker {
 	return p.maker
 }
 
+// Channels returns the names of the configured channels in sorted order, for status reporting. Channel
+// configurations, and the credentials they may hold, are not exposed.
+func (p *Plugin) Channels() []string {
+	if p.maker == nil {
+		return nil
+	}
+	return p.maker.Channels()
+}
+
 func (p *Plugin) Name() string {
 	return PluginName
 }
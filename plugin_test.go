@@ -0,0 +1,27 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPluginStopTwice(t *testing.T) {
+	p := &Plugin{maker: NewMaker(Channels{})}
+	p.Serve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("second Stop: %v", err)
+	}
+	select {
+	case <-p.done:
+	default:
+		t.Error("run still going after Stop")
+	}
+}
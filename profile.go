@@ -0,0 +1,31 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrProfilingLevel = errors.New("profiling level must be 0, 1 or 2")
+
+func (db *Database) SetProfilingLevel(ctx context.Context, level int, slowMs int) error {
+	if level < 0 || level > 2 {
+		return ErrProfilingLevel
+	}
+	return db.Database.RunCommand(ctx, bson.D{
+		{Key: "profile", Value: level},
+		{Key: "slowms", Value: slowMs},
+	}).Err()
+}
+
+func (db *Database) GetProfilingLevel(ctx context.Context) (int, int, error) {
+	var res struct {
+		Was    int `bson:"was"`
+		SlowMs int `bson:"slowms"`
+	}
+	if err := db.Database.RunCommand(ctx, bson.D{{Key: "profile", Value: -1}}).Decode(&res); err != nil {
+		return 0, 0, err
+	}
+	return res.Was, res.SlowMs, nil
+}
@@ -0,0 +1,64 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrMissingCollections = errors.New("required collections not found")
+	ErrNegativeLimit      = errors.New("limit must not be negative")
+)
+
+func (db *Database) CountMatching(ctx context.Context, collection string, filter any, limit int64) (int64, bool, error) {
+	if limit < 0 {
+		return 0, false, fmt.Errorf("%w: %d", ErrNegativeLimit, limit)
+	}
+
+	// a zero count limit means no limit, so limit+1 must stay positive: a zero limit counts at most one match to
+	// report whether any exists, and the largest limit cannot be exceeded at all
+	o := options.Count()
+	if limit < math.MaxInt64 {
+		o.SetLimit(limit + 1)
+	}
+
+	count, err := db.Collection(collection).CountDocuments(ctx, filter, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if count > limit {
+		return limit, true, nil
+	}
+	return count, false, nil
+}
+
+func (db *Database) requireCollections(ctx context.Context, names []string) error {
+	filter := bson.D{{Key: "name", Value: bson.D{{Key: "$in", Value: names}}}}
+
+	found, err := db.ListCollectionNames(ctx, filter, options.ListCollections().SetNameOnly(true))
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[string]struct{}, len(found))
+	for _, name := range found {
+		exists[name] = struct{}{}
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, ok := exists[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w in `%s`: %s", ErrMissingCollections, db.Name(), strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCountMatchingNegativeLimit(t *testing.T) {
+	db := lazyDatabase(t)
+
+	for _, limit := range []int64{-1, math.MinInt64} {
+		if _, _, err := db.CountMatching(context.Background(), "orders", bson.D{}, limit); !errors.Is(err, ErrNegativeLimit) {
+			t.Errorf("limit %d: err = %v, want ErrNegativeLimit", limit, err)
+		}
+	}
+}
+
+func TestCountMatching(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	if _, err := db.Collection("orders").InsertMany(ctx, []any{bson.D{{Key: "n", Value: 1}}, bson.D{{Key: "n", Value: 2}}, bson.D{{Key: "n", Value: 3}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filter   bson.D
+		limit    int64
+		count    int64
+		exceeded bool
+	}{
+		{filter: bson.D{}, limit: 0, count: 0, exceeded: true},
+		{filter: bson.D{{Key: "n", Value: 4}}, limit: 0, count: 0, exceeded: false},
+		{filter: bson.D{}, limit: 2, count: 2, exceeded: true},
+		{filter: bson.D{}, limit: 3, count: 3, exceeded: false},
+		{filter: bson.D{}, limit: math.MaxInt64, count: 3, exceeded: false},
+	}
+
+	for _, tt := range tests {
+		count, exceeded, err := db.CountMatching(ctx, "orders", tt.filter, tt.limit)
+		if err != nil || count != tt.count || exceeded != tt.exceeded {
+			t.Errorf("CountMatching(%v, %d) = %d, %t, %v; want %d, %t", tt.filter, tt.limit, count, exceeded, err, tt.count, tt.exceeded)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package dbmongo
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+// DelayedTag is the member tag operators are expected to set on delayed replica set members. Tag sets naming it are
+// only accepted with ReadPreferenceConfig.AllowDelayed.
+const DelayedTag = "delayed"
+
+// DefaultMaxStaleness bounds the staleness of non-primary reads configured without MaxStaleness. It is the smallest
+// bound the driver accepts, far below the delay of any delayed member.
+const DefaultMaxStaleness = 90 * time.Second
+
+// ReadPreferenceConfig selects the members reads are routed to. Hidden members never serve reads through a read
+// preference. Delayed members are kept out of non-primary reads by their staleness: without AllowDelayed those reads
+// are bounded by MaxStaleness, or DefaultMaxStaleness when it is unset, and tag sets naming DelayedTag are rejected.
+type ReadPreferenceConfig struct {
+	Mode         string              `mapstructure:"mode" json:"mode,omitempty" yaml:"mode,omitempty"`
+	Tags         []map[string]string `mapstructure:"tags" json:"tags,omitempty" yaml:"tags,omitempty"`
+	MaxStaleness time.Duration       `mapstructure:"max_staleness" json:"max_staleness,omitempty" yaml:"max_staleness,omitempty"`
+
+	// AllowDelayed permits reads from delayed members, for reads that deliberately want stale data: it lifts the
+	// DefaultMaxStaleness bound and accepts tag sets naming DelayedTag.
+	AllowDelayed bool `mapstructure:"allow_delayed" json:"allow_delayed,omitempty" yaml:"allow_delayed,omitempty"`
+}
+
+// ReadPref builds the read preference, or returns nil when no mode is configured.
+func (c ReadPreferenceConfig) ReadPref() (*readpref.ReadPref, error) {
+	if c.Mode == "" {
+		if len(c.Tags) > 0 || c.MaxStaleness != 0 {
+			return nil, fmt.Errorf("%w: read_preference mode is required with tags or max_staleness", ErrInvalidConfig)
+		}
+		return nil, nil
+	}
+
+	mode, err := readpref.ModeFromString(c.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read_preference: %w", ErrInvalidConfig, err)
+	}
+
+	maxStaleness := c.MaxStaleness
+	if !c.AllowDelayed {
+		if maxStaleness == 0 && mode != readpref.PrimaryMode {
+			maxStaleness = DefaultMaxStaleness
+		}
+		for _, set := range c.Tags {
+			if _, ok := set[DelayedTag]; ok {
+				return nil, fmt.Errorf("%w: read_preference tags target delayed members, set allow_delayed to read from them", ErrInvalidConfig)
+			}
+		}
+	}
+
+	var opts []readpref.Option
+	if len(c.Tags) > 0 {
+		opts = append(opts, readpref.WithTagSets(tag.NewTagSetsFromMaps(c.Tags)...))
+	}
+	if maxStaleness != 0 {
+		opts = append(opts, readpref.WithMaxStaleness(maxStaleness))
+	}
+
+	rp, err := readpref.New(mode, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read_preference: %w", ErrInvalidConfig, err)
+	}
+	return rp, nil
+}
@@ -0,0 +1,45 @@
+package dbmongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadPrefBoundsStaleness(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ReadPreferenceConfig
+		want time.Duration
+		set  bool
+	}{
+		{name: "untagged secondary", cfg: ReadPreferenceConfig{Mode: "secondary"}, want: DefaultMaxStaleness, set: true},
+		{name: "untagged nearest", cfg: ReadPreferenceConfig{Mode: "nearest"}, want: DefaultMaxStaleness, set: true},
+		{name: "configured bound", cfg: ReadPreferenceConfig{Mode: "secondaryPreferred", MaxStaleness: 5 * time.Minute}, want: 5 * time.Minute, set: true},
+		{name: "primary", cfg: ReadPreferenceConfig{Mode: "primary"}},
+		{name: "allow delayed", cfg: ReadPreferenceConfig{Mode: "secondary", AllowDelayed: true}},
+	}
+
+	for _, tt := range tests {
+		rp, err := tt.cfg.ReadPref()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, set := rp.MaxStaleness()
+		if set != tt.set || got != tt.want {
+			t.Errorf("%s: max staleness = %v (set %t), want %v (set %t)", tt.name, got, set, tt.want, tt.set)
+		}
+	}
+}
+
+func TestReadPrefRejectsDelayedTags(t *testing.T) {
+	cfg := ReadPreferenceConfig{Mode: "secondary", Tags: []map[string]string{{DelayedTag: "true"}}}
+	if _, err := cfg.ReadPref(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("err = %v, want ErrInvalidConfig", err)
+	}
+
+	cfg.AllowDelayed = true
+	if _, err := cfg.ReadPref(); err != nil {
+		t.Errorf("allow delayed: err = %v", err)
+	}
+}
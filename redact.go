@@ -0,0 +1,69 @@
+package dbmongo
+
+import (
+	"strings"
+)
+
+const redacted = "xxxxx"
+
+// secretDSNOptions are the lower-cased connection string options whose values are redacted.
+var secretDSNOptions = map[string]struct{}{
+	"tlscertificatekeyfilepassword":   {},
+	"sslclientcertificatekeypassword": {},
+	"authmechanismproperties":         {},
+}
+
+// RedactDSN masks the password and secret options of a connection string so it can be safely logged.
+func RedactDSN(dsn string) string {
+	scheme := strings.Index(dsn, "://")
+	if scheme < 0 {
+		return dsn
+	}
+
+	prefix, rest := dsn[:scheme+3], dsn[scheme+3:]
+
+	query := ""
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		rest, query = rest[:i], rest[i+1:]
+	}
+
+	hosts := rest
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		hosts = rest[:i]
+	}
+	if at := strings.LastIndexByte(hosts, '@'); at >= 0 {
+		if colon := strings.IndexByte(hosts[:at], ':'); colon >= 0 {
+			rest = hosts[:colon+1] + redacted + rest[at:]
+		}
+	}
+
+	if query == "" {
+		return prefix + rest
+	}
+	return prefix + rest + "?" + redactQuery(query)
+}
+
+func redactQuery(query string) string {
+	var b strings.Builder
+	for query != "" {
+		end := strings.IndexAny(query, "&;")
+		if end < 0 {
+			end = len(query)
+		}
+		b.WriteString(redactOption(query[:end]))
+		if end < len(query) {
+			b.WriteByte(query[end])
+			end++
+		}
+		query = query[end:]
+	}
+	return b.String()
+}
+
+func redactOption(option string) string {
+	key, _, found := strings.Cut(option, "=")
+	if _, ok := secretDSNOptions[strings.ToLower(key)]; ok && found {
+		return key + "=" + redacted
+	}
+	return option
+}
@@ -0,0 +1,49 @@
+package dbmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// codeNamespaceNotFound is returned when listing the indexes of a collection that does not exist on older servers.
+const codeNamespaceNotFound = 26
+
+func (db *Database) ReplaceCollectionAtomic(ctx context.Context, collection string, docs []any, batchSize int) (err error) {
+	if err = db.checkWrite(); err != nil {
+		return err
+	}
+
+	indexes, err := db.indexSpecs(ctx, collection)
+	if err != nil && !hasErrorCode(err, codeNamespaceNotFound) {
+		return err
+	}
+
+	tmp := collection + "_replace_" + primitive.NewObjectID().Hex()
+	if err = db.Database.CreateCollection(ctx, tmp); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = appendErr(err, db.Database.Collection(tmp).Drop(context.WithoutCancel(ctx)))
+		}
+	}()
+
+	if _, err = db.InsertManyBatched(ctx, tmp, docs, batchSize, true); err != nil {
+		return err
+	}
+
+	if len(indexes) > 0 {
+		err = db.Database.RunCommand(ctx, bson.D{{Key: "createIndexes", Value: tmp}, {Key: "indexes", Value: indexes}}).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.admin().RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: db.Name() + "." + tmp},
+		{Key: "to", Value: db.Name() + "." + collection},
+		{Key: "dropTarget", Value: true},
+	}).Err()
+}
@@ -0,0 +1,100 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNoPrimary = errors.New("replica set has no primary")
+
+const (
+	memberStatePrimary   = 1
+	memberStateSecondary = 2
+)
+
+// codeNoReplicationEnabled is returned by replica set commands on standalone servers.
+const codeNoReplicationEnabled = 76
+
+type replSetStatus struct {
+	Members []struct {
+		Name       string    `bson:"name"`
+		State      int       `bson:"state"`
+		OptimeDate time.Time `bson:"optimeDate"`
+	} `bson:"members"`
+}
+
+func (db *Database) replSetStatus(ctx context.Context) (replSetStatus, error) {
+	var status replSetStatus
+	err := db.admin().RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(&status)
+	if hasErrorCode(err, codeNoReplicationEnabled) {
+		return status, ErrNotReplicaSet
+	}
+	return status, err
+}
+
+func (db *Database) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	status, err := db.replSetStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		primary, oldest time.Time
+		hasPrimary      bool
+	)
+	for _, m := range status.Members {
+		switch m.State {
+		case memberStatePrimary:
+			primary, hasPrimary = m.OptimeDate, true
+		case memberStateSecondary:
+			if oldest.IsZero() || m.OptimeDate.Before(oldest) {
+				oldest = m.OptimeDate
+			}
+		}
+	}
+
+	if !hasPrimary {
+		return 0, ErrNoPrimary
+	}
+	if oldest.IsZero() || !oldest.Before(primary) {
+		return 0, nil
+	}
+	return primary.Sub(oldest), nil
+}
+
+func (db *Database) OplogWindow(ctx context.Context) (time.Duration, error) {
+	res, err := db.hello(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if res.SetName == "" {
+		return 0, ErrNotReplicaSet
+	}
+
+	// the local database of the client would read with the client read preference, not the one of the handle
+	local := db.Client().Database("local", options.Database().SetReadPreference(db.ReadPreference()))
+	oplog := local.Collection("oplog.rs")
+	projection := bson.D{{Key: "ts", Value: 1}}
+
+	var first, last struct {
+		TS primitive.Timestamp `bson:"ts"`
+	}
+	err = oplog.FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{Key: "$natural", Value: 1}}).SetProjection(projection)).Decode(&first)
+	if err == nil {
+		err = oplog.FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{Key: "$natural", Value: -1}}).SetProjection(projection)).Decode(&last)
+	}
+	if hasErrorCode(err, codeUnauthorized) {
+		return 0, fmt.Errorf("%w: %w", ErrNotAuthorized, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(last.TS.T-first.TS.T) * time.Second, nil
+}
@@ -0,0 +1,54 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Retry calls fn up to attempts times while it fails with a transient error, doubling backoff after every failed
+// attempt. Non-transient errors, such as duplicate keys, are returned immediately. When ctx is done while waiting the
+// last error of fn is returned. Only wrap operations that are safe to repeat.
+func Retry(ctx context.Context, attempts int, backoff time.Duration, fn func(context.Context) error) error {
+	return retry(ctx, nil, attempts, backoff, fn)
+}
+
+// Retry behaves like the package level Retry, but gives up with the last error once the channel RetryBudget is
+// exhausted.
+func (db *Database) Retry(ctx context.Context, attempts int, backoff time.Duration, fn func(context.Context) error) error {
+	return retry(ctx, db.budget, attempts, backoff, fn)
+}
+
+func retry(ctx context.Context, budget *retryBudget, attempts int, backoff time.Duration, fn func(context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || attempt >= attempts || !IsTransient(err) || !budget.allow() {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// IsTransient reports whether err is a network error, a timeout or a server error labeled as retryable.
+func IsTransient(err error) bool {
+	if err == nil || mongo.IsDuplicateKeyError(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+
+	var le mongo.LabeledError
+	if errors.As(err, &le) {
+		return le.HasErrorLabel("RetryableWriteError") || le.HasErrorLabel("TransientTransactionError")
+	}
+	return false
+}
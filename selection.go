@@ -0,0 +1,83 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// selectionRetryDelay is the pause before an operation that failed server selection is retried within its budget.
+const selectionRetryDelay = 100 * time.Millisecond
+
+type selectionBudgetKey struct{}
+
+// WithSelectionTimeout returns a context giving the package helpers called with it a server selection budget of d,
+// replacing the client-level ServerSelectionTimeout for those calls only. It is the per-call option of the read
+// helpers (FindProjected, FindByIDs, FindPaged, Stream, StreamInto, AggregateStream and Typed), whose variadic
+// arguments are driver options; the write helpers also accept it as the SelectionTimeout WriteOption.
+//
+// A budget shorter than the client timeout bounds each attempt by a context deadline, which covers the whole
+// round-trip, so the call fails fast during a failover. A longer budget lets the call tolerate a longer failover: an
+// attempt failing server selection after the client timeout is retried until the budget is spent. Retrying is safe
+// because an operation failing server selection never reached a server. Operations that did reach one, and methods
+// of the driver handles, are not affected.
+func WithSelectionTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, selectionBudgetKey{}, d)
+}
+
+// selectionTimeouter is implemented by databases knowing the server selection timeout of their client.
+type selectionTimeouter interface {
+	serverSelectionTimeout() time.Duration
+}
+
+func (db *Database) serverSelectionTimeout() time.Duration {
+	if db.opts == nil {
+		return defaultServerSelectionTimeout
+	}
+	return derefOr(db.opts.ServerSelectionTimeout, defaultServerSelectionTimeout)
+}
+
+// withSelection runs op within the selection budget, or the budget of ctx when budget is not positive. Without
+// either op runs once with ctx.
+func withSelection(ctx context.Context, db DB, budget time.Duration, op func(ctx context.Context) error) error {
+	if budget <= 0 {
+		budget, _ = ctx.Value(selectionBudgetKey{}).(time.Duration)
+	}
+	if budget <= 0 {
+		return op(ctx)
+	}
+
+	client := defaultServerSelectionTimeout
+	if s, ok := db.(selectionTimeouter); ok {
+		client = s.serverSelectionTimeout()
+	}
+
+	deadline := time.Now().Add(budget)
+	for {
+		attempt, cancel := ctx, context.CancelFunc(func() {})
+		if time.Until(deadline) < client {
+			// the client would wait past the budget before giving up on selection
+			attempt, cancel = context.WithDeadline(ctx, deadline)
+		}
+		err := op(attempt)
+		cancel()
+
+		if err == nil || !isSelectionError(err) || ctx.Err() != nil || time.Until(deadline) <= 0 {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(min(selectionRetryDelay, time.Until(deadline))):
+		}
+	}
+}
+
+// isSelectionError reports whether err means no suitable server was selected, so the operation was never sent.
+func isSelectionError(err error) bool {
+	var se topology.ServerSelectionError
+	return errors.As(err, &se) || errors.Is(err, topology.ErrServerSelectionTimeout)
+}
@@ -0,0 +1,75 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// selectionDatabase returns a database on an unreachable server whose client gives up server selection after
+// timeout.
+func selectionDatabase(t *testing.T, timeout time.Duration) *Database {
+	t.Helper()
+
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, Config{DSN: unreachableDSN, ServerSelectionTimeout: timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(ctx)
+	})
+	return db
+}
+
+func TestWithSelectionTimeoutShortens(t *testing.T) {
+	db := lazyDatabase(t)
+	ctx := WithSelectionTimeout(context.Background(), 100*time.Millisecond)
+
+	start := time.Now()
+	_, err := FindProjected[bson.M](ctx, db, "users", bson.D{}, bson.D{{Key: "email", Value: 1}})
+	if !isSelectionError(err) {
+		t.Fatalf("err = %v, want a server selection error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("find failed after %v, want about 100ms instead of the 30s client timeout", elapsed)
+	}
+}
+
+func TestWithSelectionTimeoutLengthens(t *testing.T) {
+	db := selectionDatabase(t, 100*time.Millisecond)
+	users := TypedCollection[bson.M](db, "users")
+
+	start := time.Now()
+	if _, err := users.FindOne(context.Background(), bson.D{}); !isSelectionError(err) {
+		t.Fatalf("without budget: err = %v, want a server selection error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("without budget: failed after %v, want the 100ms client timeout", elapsed)
+	}
+
+	start = time.Now()
+	_, err := users.FindOne(WithSelectionTimeout(context.Background(), 700*time.Millisecond), bson.D{})
+	if !isSelectionError(err) {
+		t.Fatalf("with budget: err = %v, want a server selection error", err)
+	}
+	if elapsed := time.Since(start); elapsed < 600*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("with budget: failed after %v, want about the 700ms budget", elapsed)
+	}
+}
+
+func TestSelectionTimeoutOverridesContextBudget(t *testing.T) {
+	db := selectionDatabase(t, 100*time.Millisecond)
+	ctx := WithSelectionTimeout(context.Background(), time.Minute)
+
+	start := time.Now()
+	_, err := db.InsertOneWithID(ctx, "orders", bson.M{"total": 1}, SelectionTimeout(400*time.Millisecond))
+	if !isSelectionError(err) {
+		t.Fatalf("err = %v, want a server selection error", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("insert failed after %v, want about the 400ms write budget", elapsed)
+	}
+}
@@ -0,0 +1,145 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNotSharded = errors.New("mongodb deployment is not a sharded cluster")
+
+// defaultSampleSize is the number of documents sampled when no positive sample size is given.
+const defaultSampleSize = 1000
+
+type ShardChunk struct {
+	Shard string
+	Count int64
+	Size  int64
+}
+
+// requireMongos fails with ErrNotSharded unless the client is connected to a mongos router.
+func (db *Database) requireMongos(ctx context.Context) error {
+	res, err := db.hello(ctx)
+	if err != nil {
+		return err
+	}
+	if res.Msg != "isdbgrid" {
+		return ErrNotSharded
+	}
+	return nil
+}
+
+func (db *Database) ShardDistribution(ctx context.Context, collection string) ([]ShardChunk, error) {
+	if err := db.requireMongos(ctx); err != nil {
+		return nil, err
+	}
+
+	cursor, err := db.Collection(collection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$collStats", Value: bson.D{{Key: "storageStats", Value: bson.D{}}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []struct {
+		Shard        string `bson:"shard"`
+		StorageStats struct {
+			Count int64 `bson:"count"`
+			Size  int64 `bson:"size"`
+		} `bson:"storageStats"`
+	}
+	if err = cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+
+	chunks := make([]ShardChunk, 0, len(stats))
+	for _, s := range stats {
+		chunks = append(chunks, ShardChunk{Shard: s.Shard, Count: s.StorageStats.Count, Size: s.StorageStats.Size})
+	}
+	return chunks, nil
+}
+
+func (db *Database) SampleShardKeyCardinality(ctx context.Context, collection, field string, sampleSize int) (int64, error) {
+	if sampleSize <= 0 {
+		sampleSize = defaultSampleSize
+	}
+
+	cursor, err := db.Collection(collection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sample", Value: bson.D{{Key: "size", Value: sampleSize}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$" + field}}}},
+		{{Key: "$count", Value: "distinct"}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var res []struct {
+		Distinct int64 `bson:"distinct"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+	return res[0].Distinct, nil
+}
+
+func (db *Database) SetBalancerState(ctx context.Context, enabled bool) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+	if err := db.requireMongos(ctx); err != nil {
+		return err
+	}
+
+	command := "balancerStop"
+	if enabled {
+		command = "balancerStart"
+	}
+	return db.admin().RunCommand(ctx, bson.D{{Key: command, Value: 1}}).Err()
+}
+
+func (db *Database) BalancerState(ctx context.Context) (bool, error) {
+	if err := db.requireMongos(ctx); err != nil {
+		return false, err
+	}
+
+	var status struct {
+		Mode string `bson:"mode"`
+	}
+	if err := db.admin().RunCommand(ctx, bson.D{{Key: "balancerStatus", Value: 1}}).Decode(&status); err != nil {
+		return false, err
+	}
+	return status.Mode != "off", nil
+}
+
+func (db *Database) CountOrphans(ctx context.Context, collection string) (int64, error) {
+	if err := db.requireMongos(ctx); err != nil {
+		return 0, err
+	}
+
+	cursor, err := db.Collection(collection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$collStats", Value: bson.D{{Key: "storageStats", Value: bson.D{}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "orphans", Value: bson.D{{Key: "$sum", Value: "$storageStats.numOrphanDocs"}}},
+		}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var res []struct {
+		Orphans int64 `bson:"orphans"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+	return res[0].Orphans, nil
+}
@@ -0,0 +1,92 @@
+package dbmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Stream runs a find in a goroutine and sends the decoded documents on a channel buffered to bufSize, so a slow
+// consumer holds the cursor back. The first cursor, decoding or context error is sent on the error channel. Both
+// channels are closed and the cursor released once the cursor is exhausted, an error occurs or ctx is cancelled.
+func Stream[T any](ctx context.Context, db DB, collection string, filter any, bufSize int, opts ...*options.FindOptions) (<-chan T, <-chan error) {
+	return streamCursor[T](ctx, bufSize, readTransformFor(db, collection), func() (*mongo.Cursor, error) {
+		return find(ctx, db, collection, filter, findOptions(db, collection, opts))
+	})
+}
+
+// AggregateStream runs a database-level aggregation and streams the decoded results like Stream.
+func AggregateStream[T any](ctx context.Context, db MongoDB, pipeline any, bufSize int, opts ...*options.AggregateOptions) (<-chan T, <-chan error) {
+	return streamCursor[T](ctx, bufSize, nil, func() (*mongo.Cursor, error) {
+		var cursor *mongo.Cursor
+		err := withSelection(ctx, db, 0, func(ctx context.Context) (err error) {
+			cursor, err = db.Aggregate(ctx, pipeline, opts...)
+			return err
+		})
+		return cursor, err
+	})
+}
+
+// StreamInto runs a find and calls fn for every matching document decoded into a value obtained from get, typically
+// backed by a sync.Pool, which is handed back to put once fn returns, so high-volume consumers avoid allocating a
+// value per document. Decoding only sets the fields present in the document: get must return values reset by the
+// caller, and fn must not retain the value after returning. The first decoding, cursor or fn error is returned.
+func StreamInto[T any](ctx context.Context, db DB, collection string, filter any, get func() *T, put func(*T), fn func(*T) error, opts ...*options.FindOptions) error {
+	cursor, err := find(ctx, db, collection, filter, findOptions(db, collection, opts))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	transform := readTransformFor(db, collection)
+	for cursor.Next(ctx) {
+		v := get()
+		if err = decodeCurrent(cursor, transform, v); err == nil {
+			err = fn(v)
+		}
+		put(v)
+		if err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func streamCursor[T any](ctx context.Context, bufSize int, transform ReadTransform, open func() (*mongo.Cursor, error)) (<-chan T, <-chan error) {
+	out := make(chan T, bufSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(out)
+
+		cursor, err := open()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer cursor.Close(context.WithoutCancel(ctx))
+
+		for cursor.Next(ctx) {
+			var v T
+			if err = decodeCurrent(cursor, transform, &v); err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if err = cursor.Err(); err != nil {
+			errs <- err
+		}
+	}()
+
+	return out, errs
+}
@@ -0,0 +1,65 @@
+package dbmongo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func BenchmarkStreamInto(b *testing.B) {
+	db := testDatabase(b)
+	ctx := context.Background()
+
+	var u benchUser
+	if err := bson.Unmarshal(benchUserDoc(b), &u); err != nil {
+		b.Fatal(err)
+	}
+	docs := make([]any, 1000)
+	for i := range docs {
+		u.ID = [12]byte{byte(i >> 8), byte(i)}
+		docs[i] = u
+	}
+	if _, err := db.Collection("stream_into").InsertMany(ctx, docs); err != nil {
+		b.Fatal(err)
+	}
+
+	consume := func(*benchUser) error {
+		return nil
+	}
+
+	b.Run("pooled", func(b *testing.B) {
+		pool := sync.Pool{New: func() any {
+			return new(benchUser)
+		}}
+		get := func() *benchUser {
+			v := pool.Get().(*benchUser)
+			*v = benchUser{Tags: v.Tags[:0]}
+			return v
+		}
+		put := func(v *benchUser) {
+			pool.Put(v)
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := StreamInto(ctx, db, "stream_into", bson.D{}, get, put, consume); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("fresh", func(b *testing.B) {
+		get := func() *benchUser {
+			return new(benchUser)
+		}
+		put := func(*benchUser) {}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := StreamInto(ctx, db, "stream_into", bson.D{}, get, put, consume); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
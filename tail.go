@@ -0,0 +1,101 @@
+package dbmongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tailRetryDelay is the pause before a dead tailable cursor is reopened.
+const tailRetryDelay = time.Second
+
+// cursorInvalidatedCodes are the server error codes after which a tailable cursor is reopened.
+var cursorInvalidatedCodes = []int{
+	43,  // CursorNotFound
+	136, // CappedPositionLost
+	175, // QueryPlanKilled
+	237, // CursorKilled
+}
+
+type TailOption func(*tailOptions)
+
+type tailOptions struct {
+	resumeField string
+}
+
+// ResumeField sets the field Tail resumes on after reopening its cursor, instead of _id. It must be present in every
+// tailed document and strictly increase in insertion order, such as a sequence number assigned by a single writer.
+func ResumeField(field string) TailOption {
+	return func(o *tailOptions) {
+		o.resumeField = field
+	}
+}
+
+func (db *Database) Tail(ctx context.Context, collection string, filter any, handler func(bson.Raw) error, opts ...TailOption) error {
+	o := tailOptions{resumeField: "_id"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var last bson.RawValue
+	for {
+		if err := db.tail(ctx, collection, tailFilter(filter, o.resumeField, last), handler, o.resumeField, &last); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(tailRetryDelay):
+		}
+	}
+}
+
+func (db *Database) tail(ctx context.Context, collection string, filter any, handler func(bson.Raw) error, field string, last *bson.RawValue) error {
+	cursor, err := db.Collection(collection).Find(ctx, filter, options.Find().SetCursorType(options.TailableAwait))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if isCursorInvalidated(err) {
+			return nil
+		}
+		return err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	for cursor.Next(ctx) {
+		if err = handler(cursor.Current); err != nil {
+			return err
+		}
+		// Current is reused by the next batch, the resume point must not alias it
+		if v, err := cursor.Current.LookupErr(field); err == nil {
+			*last = cloneValue(v)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err = cursor.Err(); err != nil && !isCursorInvalidated(err) {
+		return err
+	}
+	return nil
+}
+
+// tailFilter narrows filter to the documents whose field is past the last handled one.
+func tailFilter(filter any, field string, last bson.RawValue) any {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	if last.Type == 0 {
+		return filter
+	}
+	return bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: field, Value: bson.D{{Key: "$gt", Value: last}}}}}}}
+}
+
+func isCursorInvalidated(err error) bool {
+	return hasErrorCode(err, cursorInvalidatedCodes...)
+}
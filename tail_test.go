@@ -0,0 +1,66 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestTailFilter(t *testing.T) {
+	if got := tailFilter(nil, "_id", bson.RawValue{}); !sameDoc(t, got, bson.D{}) {
+		t.Errorf("tailFilter(nil) = %v, want an empty filter", got)
+	}
+
+	filter := bson.D{{Key: "level", Value: "error"}}
+	if got := tailFilter(filter, "_id", bson.RawValue{}); !sameDoc(t, got, filter) {
+		t.Errorf("tailFilter without a last document = %v, want %v", got, filter)
+	}
+
+	last := bson.RawValue{Type: bson.TypeInt32, Value: []byte{7, 0, 0, 0}}
+	want := bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: int32(7)}}}}}}}
+	if got := tailFilter(filter, "_id", last); !sameDoc(t, got, want) {
+		t.Errorf("tailFilter after 7 = %v, want %v", got, want)
+	}
+
+	want = bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: "seq", Value: bson.D{{Key: "$gt", Value: int32(7)}}}}}}}
+	if got := tailFilter(filter, "seq", last); !sameDoc(t, got, want) {
+		t.Errorf("tailFilter on seq after 7 = %v, want %v", got, want)
+	}
+}
+
+func TestTail(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	if err := db.CreateCollection(ctx, "tail", options.CreateCollection().SetCapped(true).SetSizeInBytes(1<<20)); err != nil {
+		t.Fatal(err)
+	}
+	for i := int32(1); i <= 3; i++ {
+		if _, err := db.Collection("tail").InsertOne(ctx, bson.D{{Key: "_id", Value: i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	errDone := errors.New("done")
+	var seen []int32
+	err := db.Tail(tctx, "tail", nil, func(doc bson.Raw) error {
+		seen = append(seen, doc.Lookup("_id").Int32())
+		if len(seen) == 3 {
+			return errDone
+		}
+		return nil
+	})
+	if !errors.Is(err, errDone) {
+		t.Fatalf("Tail = %v", err)
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[2] != 3 {
+		t.Errorf("tailed %v, want 1 2 3", seen)
+	}
+}
@@ -0,0 +1,67 @@
+package dbmongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type OpTiming struct {
+	Time  time.Duration
+	Count int64
+}
+
+type CollectionTiming struct {
+	Total     OpTiming
+	ReadLock  OpTiming
+	WriteLock OpTiming
+}
+
+func (db *Database) TopStats(ctx context.Context) (map[string]CollectionTiming, error) {
+	if db.requireMongos(ctx) == nil {
+		return nil, ErrMongos
+	}
+
+	var res struct {
+		Totals bson.Raw `bson:"totals"`
+	}
+	if err := db.admin().RunCommand(ctx, bson.D{{Key: "top", Value: 1}}).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	elems, err := res.Totals.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]CollectionTiming, len(elems))
+	for _, e := range elems {
+		doc, ok := e.Value().DocumentOK()
+		if !ok {
+			// the totals carry a note next to the namespaces
+			continue
+		}
+
+		var ns struct {
+			Total     topTiming `bson:"total"`
+			ReadLock  topTiming `bson:"readLock"`
+			WriteLock topTiming `bson:"writeLock"`
+		}
+		if err = bson.Unmarshal(doc, &ns); err != nil {
+			return nil, err
+		}
+		stats[e.Key()] = CollectionTiming{Total: ns.Total.timing(), ReadLock: ns.ReadLock.timing(), WriteLock: ns.WriteLock.timing()}
+	}
+	return stats, nil
+}
+
+// topTiming is a counter of the top command, whose time is in microseconds.
+type topTiming struct {
+	Time  int64 `bson:"time"`
+	Count int64 `bson:"count"`
+}
+
+func (t topTiming) timing() OpTiming {
+	return OpTiming{Time: time.Duration(t.Time) * time.Microsecond, Count: t.Count}
+}
@@ -0,0 +1,100 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNotReplicaSet = errors.New("mongodb deployment is not a replica set")
+
+type helloResult struct {
+	SetName           string    `bson:"setName"`
+	Hosts             []string  `bson:"hosts"`
+	Passives          []string  `bson:"passives"`
+	Msg               string    `bson:"msg"`
+	IsWritablePrimary bool      `bson:"isWritablePrimary"`
+	LocalTime         time.Time `bson:"localTime"`
+}
+
+// admin returns the admin database of the client.
+func (db *Database) admin() *mongo.Database {
+	return db.Client().Database("admin")
+}
+
+func (db *Database) hello(ctx context.Context) (helloResult, error) {
+	var res helloResult
+	err := db.Database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&res)
+	return res, err
+}
+
+func (db *Database) ServerTime(ctx context.Context) (time.Time, error) {
+	res, err := db.hello(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return res.LocalTime.UTC(), nil
+}
+
+// members returns the data-bearing members of the replica set that may serve reads.
+func (db *Database) members(ctx context.Context) ([]string, error) {
+	res, err := db.hello(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res.SetName == "" {
+		return nil, ErrNotReplicaSet
+	}
+	return append(res.Hosts, res.Passives...), nil
+}
+
+// directClientOptions builds options for a direct connection to host from the options of the channel, so that the
+// Config credentials, TLS, compression, concerns and timeouts apply as they do to the channel client. The settings
+// are picked one by one: the parsed connection string they were read from would make the driver reject, or SRV poll,
+// a direct connection for a mongodb+srv:// DSN, and the replica set, load balancing and SRV settings describe the
+// whole deployment.
+func (db *Database) directClientOptions(host string) *options.ClientOptions {
+	base := clientOptions(db.cfg, nil)
+
+	o := options.Client().SetHosts([]string{host}).SetDirect(true)
+	o.AppName = base.AppName
+	o.Auth = base.Auth
+	o.TLSConfig = base.TLSConfig
+	o.DisableOCSPEndpointCheck = base.DisableOCSPEndpointCheck
+	o.Compressors = base.Compressors
+	o.ZlibLevel = base.ZlibLevel
+	o.ZstdLevel = base.ZstdLevel
+	o.ConnectTimeout = base.ConnectTimeout
+	o.HeartbeatInterval = base.HeartbeatInterval
+	o.ServerSelectionTimeout = base.ServerSelectionTimeout
+	o.SocketTimeout = base.SocketTimeout
+	o.Timeout = base.Timeout
+	o.MaxConnIdleTime = base.MaxConnIdleTime
+	o.MaxPoolSize = base.MaxPoolSize
+	o.MinPoolSize = base.MinPoolSize
+	o.MaxConnecting = base.MaxConnecting
+	o.ServerMonitoringMode = base.ServerMonitoringMode
+	o.ReadConcern = base.ReadConcern
+	o.ReadPreference = base.ReadPreference
+	o.WriteConcern = base.WriteConcern
+	o.RetryReads = base.RetryReads
+	o.RetryWrites = base.RetryWrites
+	o.ServerAPIOptions = base.ServerAPIOptions
+	return o
+}
+
+// appendErr chains err1 onto err, either of which may be nil.
+func appendErr(err, err1 error) error {
+	if err == nil {
+		return err1
+	}
+	if err1 == nil {
+		return err
+	}
+	return fmt.Errorf("%w; %w", err, err1)
+}
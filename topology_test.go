@@ -0,0 +1,59 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDirectClientOptions(t *testing.T) {
+	db := &Database{cfg: Config{
+		DSN:                    "mongodb://a:27017,b:27017/app?replicaSet=rs0&tls=true&compressors=zstd&connectTimeoutMS=2500",
+		ServerSelectionTimeout: 3 * time.Second,
+		Auth:                   AuthConfig{Username: "app", Password: "secret", AuthSource: "admin"},
+	}}
+
+	o := db.directClientOptions("b:27017")
+
+	if len(o.Hosts) != 1 || o.Hosts[0] != "b:27017" || o.Direct == nil || !*o.Direct {
+		t.Errorf("hosts = %v, direct = %v; want a direct connection to b:27017", o.Hosts, o.Direct)
+	}
+	if o.ReplicaSet != nil {
+		t.Errorf("replica set = %q, want none", *o.ReplicaSet)
+	}
+	if o.Auth == nil || o.Auth.Username != "app" || o.Auth.Password != "secret" || o.Auth.AuthSource != "admin" {
+		t.Errorf("credential = %+v, want the Config credential", o.Auth)
+	}
+	if o.TLSConfig == nil || len(o.Compressors) != 1 || o.Compressors[0] != "zstd" {
+		t.Errorf("tls = %v, compressors = %v; want the DSN settings", o.TLSConfig, o.Compressors)
+	}
+	if o.ServerSelectionTimeout == nil || *o.ServerSelectionTimeout != 3*time.Second {
+		t.Errorf("server selection timeout = %v, want 3s", o.ServerSelectionTimeout)
+	}
+	if o.ConnectTimeout == nil || *o.ConnectTimeout != 2500*time.Millisecond {
+		t.Errorf("connect timeout = %v, want 2.5s", o.ConnectTimeout)
+	}
+
+	client, err := mongo.Connect(context.Background(), o)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	_ = client.Disconnect(context.Background())
+}
+
+func TestDirectClientOptionsFromSRV(t *testing.T) {
+	db := &Database{cfg: Config{DSN: "mongodb+srv://cluster.example.invalid/app", Auth: AuthConfig{Username: "app", Password: "secret"}}}
+
+	o := db.directClientOptions("a.example.invalid:27017")
+	if o.SRVServiceName != nil || o.SRVMaxHosts != nil || o.LoadBalanced != nil || o.ReplicaSet != nil {
+		t.Errorf("deployment settings copied: srv %v/%v, load balanced %v, replica set %v", o.SRVServiceName, o.SRVMaxHosts, o.LoadBalanced, o.ReplicaSet)
+	}
+
+	client, err := mongo.Connect(context.Background(), o)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	_ = client.Disconnect(context.Background())
+}
@@ -0,0 +1,105 @@
+package dbmongo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// TransactionDefaults sets the read concern, write concern and read preference of the transactions of a channel.
+// Empty fields leave the driver defaults, which inherit the client settings.
+type TransactionDefaults struct {
+	// ReadConcern is the read concern level: local, majority or snapshot.
+	ReadConcern string `mapstructure:"read_concern" json:"read_concern,omitempty" yaml:"read_concern,omitempty"`
+	// WriteConcern is majority, a number of members or a custom write concern name.
+	WriteConcern   string               `mapstructure:"write_concern" json:"write_concern,omitempty" yaml:"write_concern,omitempty"`
+	ReadPreference ReadPreferenceConfig `mapstructure:"read_preference" json:"read_preference,omitempty" yaml:"read_preference,omitempty"`
+}
+
+// options builds the transaction options, or returns nil when no default is configured.
+func (c TransactionDefaults) options() (*options.TransactionOptions, error) {
+	rp, err := c.ReadPreference.ReadPref()
+	if err != nil {
+		return nil, err
+	}
+	if c.ReadConcern == "" && c.WriteConcern == "" && rp == nil {
+		return nil, nil
+	}
+
+	o := options.Transaction()
+	switch c.ReadConcern {
+	case "":
+	case "local", "majority", "snapshot":
+		o.SetReadConcern(&readconcern.ReadConcern{Level: c.ReadConcern})
+	default:
+		return nil, fmt.Errorf("%w: unsupported transaction read_concern `%s`", ErrInvalidConfig, c.ReadConcern)
+	}
+
+	if c.WriteConcern != "" {
+		w, err := parseW(c.WriteConcern)
+		if err != nil {
+			return nil, err
+		}
+		o.SetWriteConcern(&writeconcern.WriteConcern{W: w})
+	}
+
+	if rp != nil {
+		o.SetReadPreference(rp)
+	}
+	return o, nil
+}
+
+// parseW converts a configured write concern, majority, a number of members or a custom name, to its w value.
+func parseW(s string) (any, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return s, nil
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("%w: write_concern must not be negative", ErrInvalidConfig)
+	}
+	return n, nil
+}
+
+func (db *Database) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (any, error), opts ...*options.TransactionOptions) (any, error) {
+	sess, err := db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.EndSession(context.WithoutCancel(ctx))
+
+	// validated with the config, the defaults come first so that opts override them field by field
+	if defaults, _ := db.cfg.TransactionDefaults.options(); defaults != nil {
+		opts = append([]*options.TransactionOptions{defaults}, opts...)
+	}
+	return sess.WithTransaction(ctx, fn, opts...)
+}
+
+func (db *Database) CausalScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := db.Client().StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.WithoutCancel(ctx))
+
+	return fn(mongo.NewSessionContext(ctx, sess))
+}
+
+// WithTransactionTyped runs fn in a transaction like Database.WithTransaction and returns its result as T.
+func WithTransactionTyped[T any](ctx context.Context, db *Database, fn func(sessCtx mongo.SessionContext) (T, error), opts ...*options.TransactionOptions) (T, error) {
+	res, err := db.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return fn(sessCtx)
+	}, opts...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	v, _ := res.(T)
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var errInsufficientFunds = errors.New("insufficient funds")
+
+type account struct {
+	ID      string `bson:"_id"`
+	Balance int64  `bson:"balance"`
+}
+
+// transfer moves amount from the source account to the destination one, kept in different collections, and returns
+// the new source balance.
+func transfer(ctx context.Context, db *Database, from, to string, amount int64) (int64, error) {
+	return WithTransactionTyped(ctx, db, func(sessCtx mongo.SessionContext) (int64, error) {
+		var src account
+		err := db.Collection("checking").FindOneAndUpdate(sessCtx,
+			bson.D{{Key: "_id", Value: from}},
+			bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: -amount}}}},
+		).Decode(&src)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = db.Collection("savings").UpdateByID(sessCtx, to, bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: amount}}}}); err != nil {
+			return 0, err
+		}
+
+		// the debit above is rolled back with the transaction
+		if src.Balance < amount {
+			return 0, errInsufficientFunds
+		}
+		return src.Balance - amount, nil
+	})
+}
+
+func TestWithTransactionTypedTransfer(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	if res, err := db.hello(ctx); err != nil || res.SetName == "" {
+		t.Skipf("transactions need a replica set: %v", err)
+	}
+
+	if _, err := db.Collection("checking").InsertOne(ctx, account{ID: "alice", Balance: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Collection("savings").InsertOne(ctx, account{ID: "alice", Balance: 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	balances := func() (int64, int64) {
+		t.Helper()
+
+		var src, dst account
+		if err := db.Collection("checking").FindOne(ctx, bson.D{{Key: "_id", Value: "alice"}}).Decode(&src); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Collection("savings").FindOne(ctx, bson.D{{Key: "_id", Value: "alice"}}).Decode(&dst); err != nil {
+			t.Fatal(err)
+		}
+		return src.Balance, dst.Balance
+	}
+
+	left, err := transfer(ctx, db, "alice", "alice", 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left != 70 {
+		t.Errorf("returned balance = %d, want 70", left)
+	}
+	if src, dst := balances(); src != 70 || dst != 40 {
+		t.Errorf("after transfer: checking %d, savings %d; want 70 and 40", src, dst)
+	}
+
+	if _, err = transfer(ctx, db, "alice", "alice", 500); !errors.Is(err, errInsufficientFunds) {
+		t.Fatalf("overdraft: err = %v, want errInsufficientFunds", err)
+	}
+	if src, dst := balances(); src != 70 || dst != 40 {
+		t.Errorf("after rollback: checking %d, savings %d; want 70 and 40", src, dst)
+	}
+}
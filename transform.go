@@ -0,0 +1,78 @@
+package dbmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReadTransform rewrites a document read from a collection before it is decoded, for example to upgrade documents
+// stored in an older schema version on the fly. It must not retain or modify its argument.
+type ReadTransform func(bson.Raw) (bson.Raw, error)
+
+// readTransformer is implemented by databases carrying ReadTransforms for the typed read helpers.
+type readTransformer interface {
+	readTransform(collection string) ReadTransform
+}
+
+func (db *Database) readTransform(collection string) ReadTransform {
+	return db.transforms[collection]
+}
+
+// readTransformFor returns the ReadTransform db applies to the collection, or nil.
+func readTransformFor(db DB, collection string) ReadTransform {
+	if t, ok := db.(readTransformer); ok {
+		return t.readTransform(collection)
+	}
+	return nil
+}
+
+// decodeRaw decodes raw into v after applying transform, when not nil.
+func decodeRaw(transform ReadTransform, raw bson.Raw, v any) error {
+	raw, err := transform(raw)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, v)
+}
+
+// decodeCurrent decodes the current document of cursor into v, applying transform when not nil.
+func decodeCurrent(cursor *mongo.Cursor, transform ReadTransform, v any) error {
+	if transform == nil {
+		return cursor.Decode(v)
+	}
+	return decodeRaw(transform, cursor.Current, v)
+}
+
+// decodeOne decodes the document of res into v, applying transform when not nil.
+func decodeOne(res *mongo.SingleResult, transform ReadTransform, v any) error {
+	if transform == nil {
+		return res.Decode(v)
+	}
+	raw, err := res.DecodeBytes()
+	if err != nil {
+		return err
+	}
+	return decodeRaw(transform, raw, v)
+}
+
+// decodeAll decodes the remaining documents of cursor into a slice of T, applying transform when not nil, and closes
+// the cursor.
+func decodeAll[T any](ctx context.Context, cursor *mongo.Cursor, transform ReadTransform) ([]T, error) {
+	var out []T
+	if transform == nil {
+		err := cursor.All(ctx, &out)
+		return out, err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	for cursor.Next(ctx) {
+		var v T
+		if err := decodeRaw(transform, cursor.Current, &v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, cursor.Err()
+}
@@ -0,0 +1,125 @@
+package dbmongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (db *Database) TruncateDatabase(ctx context.Context) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+
+	names, err := db.ListCollectionNames(ctx, bson.D{{Key: "type", Value: bson.D{{Key: "$in", Value: bson.A{"collection", "timeseries"}}}}}, options.ListCollections().SetNameOnly(true))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") {
+			continue
+		}
+		if err1 := db.Collection(name).Drop(ctx); err1 != nil {
+			err = appendErr(err, fmt.Errorf("drop `%s`: %w", name, err1))
+		}
+	}
+	return err
+}
+
+func (db *Database) TruncateCollection(ctx context.Context, collection string) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+
+	create, indexes, err := db.recreateCommands(ctx, collection)
+	if err != nil || create == nil {
+		_, err = db.Collection(collection).DeleteMany(ctx, bson.D{})
+		return err
+	}
+
+	if err = db.Collection(collection).Drop(ctx); err != nil {
+		_, err = db.Collection(collection).DeleteMany(ctx, bson.D{})
+		return err
+	}
+
+	if err = db.Database.RunCommand(ctx, create).Err(); err != nil {
+		return fmt.Errorf("recreate `%s`: %w", collection, err)
+	}
+	if indexes != nil {
+		if err = db.Database.RunCommand(ctx, indexes).Err(); err != nil {
+			return fmt.Errorf("recreate indexes of `%s`: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// recreateCommands returns the create and createIndexes commands reproducing the collection with its options and
+// indexes, or a nil create command when the collection cannot be dropped and recreated safely.
+func (db *Database) recreateCommands(ctx context.Context, collection string) (bson.D, bson.D, error) {
+	if db.requireMongos(ctx) == nil {
+		// dropping would discard the sharding configuration
+		return nil, nil, nil
+	}
+
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: collection}})
+	if err != nil || len(specs) != 1 || specs[0].Type != "collection" {
+		return nil, nil, err
+	}
+
+	create := bson.D{{Key: "create", Value: collection}}
+	if specs[0].Options != nil {
+		elems, err := specs[0].Options.Elements()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range elems {
+			create = append(create, bson.E{Key: e.Key(), Value: e.Value()})
+		}
+	}
+
+	indexes, err := db.indexSpecs(ctx, collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(indexes) == 0 {
+		return create, nil, nil
+	}
+	return create, bson.D{{Key: "createIndexes", Value: collection}, {Key: "indexes", Value: indexes}}, nil
+}
+
+// indexSpecs returns the specifications of the secondary indexes of the collection, ready for createIndexes.
+func (db *Database) indexSpecs(ctx context.Context, collection string) (bson.A, error) {
+	cursor, err := db.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []bson.D
+	if err = cursor.All(ctx, &all); err != nil {
+		return nil, err
+	}
+
+	var indexes bson.A
+	for _, index := range all {
+		spec := make(bson.D, 0, len(index))
+		isID := false
+		for _, e := range index {
+			switch {
+			case e.Key == "ns":
+				continue
+			case e.Key == "name" && e.Value == "_id_":
+				isID = true
+			}
+			spec = append(spec, e)
+		}
+		if !isID {
+			indexes = append(indexes, spec)
+		}
+	}
+	return indexes, nil
+}
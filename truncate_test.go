@@ -0,0 +1,36 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestTruncateDatabaseDropsTimeseries(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	if err := db.CreateCollection(ctx, "plain"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateCollection(ctx, "metrics", options.CreateCollection().SetTimeSeriesOptions(options.TimeSeries().SetTimeField("ts"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateView(ctx, "plain_view", "plain", bson.A{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.TruncateDatabase(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := db.ListCollectionNames(ctx, bson.D{{Key: "name", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$regex", Value: "^system\\."}}}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "plain_view" {
+		t.Errorf("collections left = %v, want only plain_view", names)
+	}
+}
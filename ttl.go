@@ -0,0 +1,49 @@
+package dbmongo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ttlTracker remembers the first sample seeing the current metrics.ttl.passes count of the server.
+type ttlTracker struct {
+	mu     sync.Mutex
+	passes int64
+	seen   time.Time
+}
+
+// observe records a sample of passes taken at server time at and returns the time the last pass is known to have
+// completed by, zero when the monitor has not completed any pass.
+func (t *ttlTracker) observe(passes int64, at time.Time) time.Time {
+	if passes == 0 {
+		return time.Time{}
+	}
+	if t == nil {
+		return at
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if passes != t.passes || t.seen.IsZero() {
+		t.passes, t.seen = passes, at
+	}
+	return t.seen
+}
+
+func (db *Database) TTLMonitorStatus(ctx context.Context) (time.Time, int64, error) {
+	var status struct {
+		LocalTime time.Time `bson:"localTime"`
+		Metrics   struct {
+			TTL struct {
+				DeletedDocuments int64 `bson:"deletedDocuments"`
+				Passes           int64 `bson:"passes"`
+			} `bson:"ttl"`
+		} `bson:"metrics"`
+	}
+	if err := db.serverStatus(ctx, &status); err != nil {
+		return time.Time{}, 0, err
+	}
+	return db.ttl.observe(status.Metrics.TTL.Passes, status.LocalTime.UTC()), status.Metrics.TTL.DeletedDocuments, nil
+}
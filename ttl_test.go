@@ -0,0 +1,48 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTTLTrackerLastPass(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := new(ttlTracker)
+
+	if got := tr.observe(0, t0); !got.IsZero() {
+		t.Errorf("no pass: last pass = %v, want zero", got)
+	}
+	if got := tr.observe(3, t0.Add(time.Minute)); !got.Equal(t0.Add(time.Minute)) {
+		t.Errorf("first sample: last pass = %v, want the sample time", got)
+	}
+	if got := tr.observe(3, t0.Add(2*time.Minute)); !got.Equal(t0.Add(time.Minute)) {
+		t.Errorf("stalled monitor: last pass = %v, want the first sample of 3 passes", got)
+	}
+	if got := tr.observe(4, t0.Add(3*time.Minute)); !got.Equal(t0.Add(3 * time.Minute)) {
+		t.Errorf("new pass: last pass = %v, want the sample time", got)
+	}
+}
+
+func TestTTLMonitorStatus(t *testing.T) {
+	db := testDatabase(t)
+
+	last, deleted, err := db.TTLMonitorStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted < 0 {
+		t.Errorf("deleted documents = %d", deleted)
+	}
+	if !last.IsZero() && last.After(time.Now().Add(time.Minute)) {
+		t.Errorf("last pass %v is in the future", last)
+	}
+
+	again, _, err := db.TTLMonitorStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Before(last) {
+		t.Errorf("last pass went back from %v to %v", last, again)
+	}
+}
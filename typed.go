@@ -0,0 +1,99 @@
+package dbmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Typed is a repository-style handle on a collection whose documents decode into T. Reads apply the channel defaults
+// of the find helpers, writes the BypassDocumentValidation default of the write helpers. The collection handle is
+// resolved from db on every call, where it is cheap to build, and the struct field metadata of T is cached by the
+// driver's struct codec after the first use, so repeated calls only pay for the round-trip and the decoding itself.
+type Typed[T any] struct {
+	db   DB
+	name string
+}
+
+func TypedCollection[T any](db DB, name string) *Typed[T] {
+	return &Typed[T]{db: db, name: name}
+}
+
+// Collection returns the underlying collection.
+func (t *Typed[T]) Collection() *mongo.Collection {
+	return t.db.Collection(t.name)
+}
+
+func (t *Typed[T]) FindOne(ctx context.Context, filter any, opts ...*options.FindOneOptions) (T, error) {
+	var res *mongo.SingleResult
+	_ = withSelection(ctx, t.db, 0, func(ctx context.Context) error {
+		res = t.Collection().FindOne(ctx, filter, findOneOptions(t.db, t.name, opts))
+		return res.Err()
+	})
+
+	var v T
+	err := decodeOne(res, readTransformFor(t.db, t.name), &v)
+	return v, err
+}
+
+func (t *Typed[T]) Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]T, error) {
+	cursor, err := find(ctx, t.db, t.name, filter, findOptions(t.db, t.name, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAll[T](ctx, cursor, readTransformFor(t.db, t.name))
+}
+
+func (t *Typed[T]) InsertOne(ctx context.Context, doc T, opts ...*options.InsertOneOptions) (any, error) {
+	if err := checkWrite(t.db); err != nil {
+		return nil, err
+	}
+
+	o := insertOneOptions(t.db, opts)
+
+	var res *mongo.InsertOneResult
+	err := withSelection(ctx, t.db, 0, func(ctx context.Context) (err error) {
+		res, err = t.Collection().InsertOne(ctx, doc, o)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.InsertedID, nil
+}
+
+func (t *Typed[T]) UpdateByID(ctx context.Context, id any, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := checkWrite(t.db); err != nil {
+		return nil, err
+	}
+
+	o := updateOptions(t.db, opts)
+
+	var res *mongo.UpdateResult
+	err := withSelection(ctx, t.db, 0, func(ctx context.Context) (err error) {
+		res, err = t.Collection().UpdateByID(ctx, id, update, o)
+		return err
+	})
+	return res, err
+}
+
+// insertOneOptions merges opts and completes them with the channel BypassDocumentValidation default when db carries
+// one.
+func insertOneOptions(db DB, opts []*options.InsertOneOptions) *options.InsertOneOptions {
+	o := options.MergeInsertOneOptions(opts...)
+	if o.BypassDocumentValidation == nil && writeDefaults(db).bypassDocumentValidation {
+		o.SetBypassDocumentValidation(true)
+	}
+	return o
+}
+
+// updateOptions merges opts and completes them with the channel BypassDocumentValidation default when db carries one.
+func updateOptions(db DB, opts []*options.UpdateOptions) *options.UpdateOptions {
+	o := options.MergeUpdateOptions(opts...)
+	if o.BypassDocumentValidation == nil && writeDefaults(db).bypassDocumentValidation {
+		o.SetBypassDocumentValidation(true)
+	}
+	return o
+}
@@ -0,0 +1,199 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type benchUser struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Email   string             `bson:"email"`
+	Name    string             `bson:"name"`
+	Age     int                `bson:"age"`
+	Tags    []string           `bson:"tags"`
+	Created time.Time          `bson:"created"`
+}
+
+func TestFindOneOptionsDefaults(t *testing.T) {
+	db := &Database{cfg: Config{
+		DefaultSort:          bson.D{{Key: "created", Value: -1}},
+		DefaultHints:         map[string]string{"users": "email_1"},
+		DefaultExcludeFields: map[string][]string{"users": {"password"}},
+	}}
+
+	o := findOneOptions(db, "users", nil)
+	if !sameDoc(t, o.Sort, bson.D{{Key: "created", Value: -1}}) || o.Hint != "email_1" || !sameDoc(t, o.Projection, bson.D{{Key: "password", Value: 0}}) {
+		t.Errorf("defaults not applied: sort %v, hint %v, projection %v", o.Sort, o.Hint, o.Projection)
+	}
+
+	o = findOneOptions(db, "users", []*options.FindOneOptions{
+		options.FindOne().SetSort(bson.D{{Key: "name", Value: 1}}).SetHint("name_1").SetProjection(bson.D{{Key: "name", Value: 1}}),
+	})
+	if !sameDoc(t, o.Sort, bson.D{{Key: "name", Value: 1}}) || o.Hint != "name_1" || !sameDoc(t, o.Projection, bson.D{{Key: "name", Value: 1}}) {
+		t.Errorf("caller options overridden: sort %v, hint %v, projection %v", o.Sort, o.Hint, o.Projection)
+	}
+
+	if o = findOneOptions(db, "orders", nil); o.Hint != nil || o.Projection != nil {
+		t.Errorf("defaults of users applied to orders: hint %v, projection %v", o.Hint, o.Projection)
+	}
+}
+
+func TestTypedWriteOptionsDefaults(t *testing.T) {
+	db := &Database{cfg: Config{BypassDocumentValidation: true}}
+
+	if o := insertOneOptions(db, nil); o.BypassDocumentValidation == nil || !*o.BypassDocumentValidation {
+		t.Errorf("insert bypass = %v, want the channel default", o.BypassDocumentValidation)
+	}
+	if o := updateOptions(db, nil); o.BypassDocumentValidation == nil || !*o.BypassDocumentValidation {
+		t.Errorf("update bypass = %v, want the channel default", o.BypassDocumentValidation)
+	}
+
+	o := updateOptions(db, []*options.UpdateOptions{options.Update().SetBypassDocumentValidation(false)})
+	if o.BypassDocumentValidation == nil || *o.BypassDocumentValidation {
+		t.Errorf("update bypass = %v, want the caller false", o.BypassDocumentValidation)
+	}
+
+	if o := insertOneOptions(&Database{}, nil); o.BypassDocumentValidation != nil {
+		t.Errorf("insert bypass = %v without a channel default", o.BypassDocumentValidation)
+	}
+}
+
+func TestTypedResolvesCollectionPerCall(t *testing.T) {
+	db := lazyDatabase(t)
+	users := TypedCollection[benchUser](db, "users")
+
+	other := db.ForDatabase("other")
+	users.db = other
+	if got := users.Collection().Database().Name(); got != "other" {
+		t.Errorf("collection database = %q, want other", got)
+	}
+}
+
+func sameDoc(t *testing.T, got any, want bson.D) bool {
+	t.Helper()
+
+	if got == nil {
+		return false
+	}
+	return sameIndexKeys(mustMarshal(t, got), mustMarshal(t, want))
+}
+
+func benchUserDoc(b *testing.B) bson.Raw {
+	b.Helper()
+
+	raw, err := bson.Marshal(benchUser{
+		ID:      primitive.NewObjectID(),
+		Email:   "ada@example.com",
+		Name:    "Ada Lovelace",
+		Age:     36,
+		Tags:    []string{"admin", "math", "engines"},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return raw
+}
+
+func BenchmarkUnmarshalRaw(b *testing.B) {
+	raw := benchUserDoc(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var u benchUser
+		if err := bson.Unmarshal(raw, &u); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTypedDecodeOne(b *testing.B) {
+	raw := benchUserDoc(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var u benchUser
+		if err := decodeOne(mongo.NewSingleResultFromDocument(raw, nil, nil), nil, &u); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTypedDecodeAll(b *testing.B) {
+	raw := benchUserDoc(b)
+	docs := make([]any, 100)
+	for i := range docs {
+		docs[i] = raw
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = decodeAll[benchUser](ctx, cursor, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalRawAll(b *testing.B) {
+	raw := benchUserDoc(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var out []benchUser
+		for n := 0; n < 100; n++ {
+			var u benchUser
+			if err := bson.Unmarshal(raw, &u); err != nil {
+				b.Fatal(err)
+			}
+			out = append(out, u)
+		}
+	}
+}
+
+func BenchmarkTypedFindOne(b *testing.B) {
+	db := testDatabase(b)
+	ctx := context.Background()
+
+	var u benchUser
+	if err := bson.Unmarshal(benchUserDoc(b), &u); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := db.Collection("users").InsertOne(ctx, u); err != nil {
+		b.Fatal(err)
+	}
+	users := TypedCollection[benchUser](db, "users")
+	filter := bson.D{{Key: "_id", Value: u.ID}}
+
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			raw, err := db.Collection("users").FindOne(ctx, filter).DecodeBytes()
+			if err != nil {
+				b.Fatal(err)
+			}
+			var v benchUser
+			if err = bson.Unmarshal(raw, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("typed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := users.FindOne(ctx, filter); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
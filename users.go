@@ -0,0 +1,70 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrUserExists = errors.New("mongodb user already exists")
+
+const (
+	// codeUnauthorized is returned by commands the authenticated user lacks the privileges to run.
+	codeUnauthorized = 13
+	// codeUserExists is returned by createUser when the user is already defined.
+	codeUserExists = 51003
+)
+
+type Role struct {
+	Role string `bson:"role"`
+	DB   string `bson:"db"`
+}
+
+type UserInfo struct {
+	User       string   `bson:"user"`
+	DB         string   `bson:"db"`
+	Roles      []Role   `bson:"roles"`
+	Mechanisms []string `bson:"mechanisms"`
+}
+
+func (db *Database) ListUsers(ctx context.Context) ([]UserInfo, error) {
+	// credentials are only returned with showCredentials, and UserInfo has no field to hold them anyway
+	var res struct {
+		Users []UserInfo `bson:"users"`
+	}
+	err := db.Database.RunCommand(ctx, bson.D{{Key: "usersInfo", Value: 1}}).Decode(&res)
+	if hasErrorCode(err, codeUnauthorized) {
+		return nil, fmt.Errorf("%w: %w", ErrNotAuthorized, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res.Users, nil
+}
+
+func (db *Database) CreateUser(ctx context.Context, username, password string, roles []Role) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+
+	grants := make(bson.A, 0, len(roles))
+	for _, r := range roles {
+		if r.DB == "" {
+			r.DB = db.Name()
+		}
+		grants = append(grants, r)
+	}
+
+	// the driver redacts createUser from command monitoring, so the password never reaches the logs
+	err := db.Database.RunCommand(ctx, bson.D{
+		{Key: "createUser", Value: username},
+		{Key: "pwd", Value: password},
+		{Key: "roles", Value: grants},
+	}).Err()
+	if hasErrorCode(err, codeUserExists) {
+		return fmt.Errorf("%w: %s", ErrUserExists, username)
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package dbmongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (db *Database) GetValidator(ctx context.Context, collection string) (bson.M, string, string, error) {
+	cursor, err := db.ListCollections(ctx, bson.D{{Key: "name", Value: collection}})
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var specs []struct {
+		Options struct {
+			Validator        bson.M `bson:"validator"`
+			ValidationLevel  string `bson:"validationLevel"`
+			ValidationAction string `bson:"validationAction"`
+		} `bson:"options"`
+	}
+	if err = cursor.All(ctx, &specs); err != nil {
+		return nil, "", "", err
+	}
+	if len(specs) == 0 {
+		return nil, "", "", fmt.Errorf("%w: %s", ErrMissingCollections, collection)
+	}
+
+	o := specs[0].Options
+	return o.Validator, o.ValidationLevel, o.ValidationAction, nil
+}
@@ -0,0 +1,42 @@
+package dbmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type ViewInfo struct {
+	Name     string
+	ViewOn   string
+	Pipeline []bson.D
+}
+
+func (db *Database) ListViews(ctx context.Context, filter any) ([]ViewInfo, error) {
+	viewFilter := bson.D{{Key: "type", Value: "view"}}
+	if filter != nil {
+		viewFilter = bson.D{{Key: "$and", Value: bson.A{filter, viewFilter}}}
+	}
+
+	cursor, err := db.ListCollections(ctx, viewFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []struct {
+		Name    string `bson:"name"`
+		Options struct {
+			ViewOn   string   `bson:"viewOn"`
+			Pipeline []bson.D `bson:"pipeline"`
+		} `bson:"options"`
+	}
+	if err = cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+
+	views := make([]ViewInfo, 0, len(specs))
+	for _, spec := range specs {
+		views = append(views, ViewInfo{Name: spec.Name, ViewOn: spec.Options.ViewOn, Pipeline: spec.Options.Pipeline})
+	}
+	return views, nil
+}
@@ -0,0 +1,172 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrZeroTimestamp      = errors.New("change stream start timestamp must not be zero")
+	ErrUnsupportedVersion = errors.New("mongodb server version does not support the operation")
+)
+
+// watchRetryDelay is the pause before a failed change stream is recreated.
+const watchRetryDelay = time.Second
+
+func (db *Database) WatchFrom(ctx context.Context, ts primitive.Timestamp, pipeline any, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error) {
+	if ts.IsZero() {
+		return nil, ErrZeroTimestamp
+	}
+	return db.Watch(ctx, pipeline, append(opts[:len(opts):len(opts)], options.ChangeStream().SetStartAtOperationTime(&ts))...)
+}
+
+func (db *Database) WatchCluster(ctx context.Context, pipeline any, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error) {
+	return db.Client().Watch(ctx, pipeline, opts...)
+}
+
+func (db *Database) WatchDebounced(ctx context.Context, pipeline any, window time.Duration, handler func(events []bson.Raw) error, opts ...*options.ChangeStreamOptions) error {
+	// await at most one window per getMore so a batch is delivered soon after its window closes
+	opts = append([]*options.ChangeStreamOptions{options.ChangeStream().SetMaxAwaitTime(window)}, opts...)
+
+	cs, err := db.Watch(ctx, pipeline, opts...)
+	if err != nil {
+		return err
+	}
+	defer cs.Close(context.WithoutCancel(ctx))
+
+	for cs.Next(ctx) {
+		events := []bson.Raw{slices.Clone(cs.Current)}
+		for deadline := time.Now().Add(window); time.Now().Before(deadline); {
+			if cs.TryNext(ctx) {
+				events = append(events, slices.Clone(cs.Current))
+				continue
+			}
+			if cs.Err() != nil || ctx.Err() != nil {
+				break
+			}
+		}
+		if cs.Err() != nil || ctx.Err() != nil {
+			break
+		}
+
+		if err = handler(events); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return cs.Err()
+}
+
+func (db *Database) WatchForever(ctx context.Context, pipeline any, handler func(bson.Raw) error, opts ...*options.ChangeStreamOptions) error {
+	var token bson.Raw
+	for {
+		err := db.watchOnce(ctx, pipeline, handler, &token, opts)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var he *handlerError
+		if errors.As(err, &he) {
+			return he.error
+		}
+		if err == nil || !isResumable(err) {
+			return err
+		}
+		db.logger().Warn("change stream failed, resuming", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// watchOnce consumes a change stream resumed after token until it fails or is invalidated, keeping token at the last
+// handled event or, while no events arrive, at the resume token of the last empty batch.
+func (db *Database) watchOnce(ctx context.Context, pipeline any, handler func(bson.Raw) error, token *bson.Raw, opts []*options.ChangeStreamOptions) error {
+	if *token != nil {
+		opts = resumeOptions(opts, *token)
+	}
+
+	cs, err := db.Watch(ctx, pipeline, opts...)
+	if err != nil {
+		return err
+	}
+	defer cs.Close(context.WithoutCancel(ctx))
+
+	for {
+		if cs.TryNext(ctx) {
+			if err = handler(cs.Current); err != nil {
+				return &handlerError{err}
+			}
+		} else if cs.Err() != nil || cs.ID() == 0 {
+			return cs.Err()
+		}
+		if rt := cs.ResumeToken(); rt != nil {
+			*token = slices.Clone(rt)
+		}
+	}
+}
+
+// resumeOptions merges opts into options resuming after token. The caller's start options are dropped since the
+// server rejects more than one of them and the stream must continue from token, not from where it first started.
+func resumeOptions(opts []*options.ChangeStreamOptions, token bson.Raw) []*options.ChangeStreamOptions {
+	o := options.MergeChangeStreamOptions(opts...)
+	o.StartAtOperationTime = nil
+	o.StartAfter = nil
+	return []*options.ChangeStreamOptions{o.SetResumeAfter(token)}
+}
+
+// handlerError marks an error returned by a handler so that it is never mistaken for a resumable stream error.
+type handlerError struct {
+	error
+}
+
+// isResumable reports whether a change stream failing with err can be recreated from its last resume token.
+func isResumable(err error) bool {
+	var le mongo.LabeledError
+	if errors.As(err, &le) && le.HasErrorLabel("ResumableChangeStreamError") {
+		return true
+	}
+	return IsTransient(err) || IsNotPrimary(err) || IsShutdownInProgress(err) || isCursorInvalidated(err)
+}
+
+func (db *Database) SetChangeStreamPreAndPostImages(ctx context.Context, collection string, enabled bool) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+	if err := db.requireVersion(ctx, 6); err != nil {
+		return err
+	}
+
+	return db.Database.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "changeStreamPreAndPostImages", Value: bson.D{{Key: "enabled", Value: enabled}}},
+	}).Err()
+}
+
+// requireVersion fails with ErrUnsupportedVersion unless the server major version is at least major.
+func (db *Database) requireVersion(ctx context.Context, major int) error {
+	var info struct {
+		Version      string `bson:"version"`
+		VersionArray []int  `bson:"versionArray"`
+	}
+	if err := db.Database.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info); err != nil {
+		return err
+	}
+	if len(info.VersionArray) == 0 || info.VersionArray[0] < major {
+		return fmt.Errorf("%w: %s, %d.0 or later is required", ErrUnsupportedVersion, info.Version, major)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package dbmongo
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestResumeOptions(t *testing.T) {
+	token := mustMarshal(t, bson.D{{Key: "_data", Value: "8263"}})
+	start := options.ChangeStream().
+		SetStartAtOperationTime(&primitive.Timestamp{T: 1}).
+		SetStartAfter(bson.D{{Key: "_data", Value: "8262"}}).
+		SetResumeAfter(bson.D{{Key: "_data", Value: "8261"}})
+	opts := []*options.ChangeStreamOptions{start, options.ChangeStream().SetFullDocument(options.UpdateLookup).SetMaxAwaitTime(time.Second)}
+
+	got := resumeOptions(opts, token)
+	if len(got) != 1 {
+		t.Fatalf("got %d options, want 1", len(got))
+	}
+
+	o := got[0]
+	if o.StartAtOperationTime != nil || o.StartAfter != nil {
+		t.Errorf("start options kept: startAtOperationTime %v, startAfter %v", o.StartAtOperationTime, o.StartAfter)
+	}
+	if rt, ok := o.ResumeAfter.(bson.Raw); !ok || !bytes.Equal(rt, token) {
+		t.Errorf("resumeAfter = %v, want %v", o.ResumeAfter, token)
+	}
+	if o.FullDocument == nil || *o.FullDocument != options.UpdateLookup || o.MaxAwaitTime == nil || *o.MaxAwaitTime != time.Second {
+		t.Errorf("other options lost: %+v", o)
+	}
+	if start.StartAtOperationTime == nil || start.StartAfter == nil {
+		t.Error("caller options modified")
+	}
+}
@@ -0,0 +1,227 @@
+package dbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrNotObjectID     = errors.New("document _id is not an ObjectID")
+	ErrVersionConflict = errors.New("document version conflict")
+)
+
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	bypassDocumentValidation bool
+	skipDuplicates           bool
+	selectionTimeout         time.Duration
+}
+
+// BypassDocumentValidation overrides the channel BypassDocumentValidation default for a single write helper call.
+func BypassDocumentValidation(bypass bool) WriteOption {
+	return func(o *writeOptions) {
+		o.bypassDocumentValidation = bypass
+	}
+}
+
+// SkipDuplicates makes CopyCollectionTo keep documents already present in the destination instead of failing on
+// duplicate key errors. Other helpers ignore it.
+func SkipDuplicates() WriteOption {
+	return func(o *writeOptions) {
+		o.skipDuplicates = true
+	}
+}
+
+// SelectionTimeout sets the server selection budget of a single write helper call, replacing the client-level
+// ServerSelectionTimeout and any budget set on ctx with WithSelectionTimeout, which documents how a budget shortens
+// or lengthens selection.
+func SelectionTimeout(d time.Duration) WriteOption {
+	return func(o *writeOptions) {
+		o.selectionTimeout = d
+	}
+}
+
+func (db *Database) writeOptions(opts []WriteOption) writeOptions {
+	o := writeOptions{bypassDocumentValidation: db.cfg.BypassDocumentValidation}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// writeDefaulter is implemented by databases carrying channel defaults for the write helpers.
+type writeDefaulter interface {
+	writeOptions(opts []WriteOption) writeOptions
+}
+
+// writeDefaults returns the channel defaults of the write helpers when db carries any.
+func writeDefaults(db DB) writeOptions {
+	if d, ok := db.(writeDefaulter); ok {
+		return d.writeOptions(nil)
+	}
+	return writeOptions{}
+}
+
+// VersionField holds the document version checked and incremented by UpdateWithVersion.
+const VersionField = "_version"
+
+func (db *Database) InsertOneWithID(ctx context.Context, collection string, doc bson.M, opts ...WriteOption) (primitive.ObjectID, error) {
+	if err := db.checkWrite(); err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, ok := doc["_id"]
+	if !ok {
+		id = primitive.NewObjectID()
+		doc["_id"] = id
+	}
+
+	oid, ok := id.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, ErrNotObjectID
+	}
+
+	o := db.writeOptions(opts)
+
+	insertOpts := options.InsertOne()
+	if o.bypassDocumentValidation {
+		insertOpts.SetBypassDocumentValidation(true)
+	}
+
+	err := withSelection(ctx, db, o.selectionTimeout, func(ctx context.Context) error {
+		_, err := db.Collection(collection).InsertOne(ctx, doc, insertOpts)
+		return err
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return oid, nil
+}
+
+type BatchInsertResult struct {
+	// InsertedCount is the number of documents inserted across all batches.
+	InsertedCount int64
+
+	// Failed maps the index of every failed batch to the errors of its rejected documents.
+	Failed map[int][]DocumentError
+}
+
+type DocumentError struct {
+	// Index is the position of the document in the docs passed to InsertManyBatched.
+	Index int
+
+	mongo.WriteError
+}
+
+func (db *Database) InsertManyBatched(ctx context.Context, collection string, docs []any, batchSize int, ordered bool, opts ...WriteOption) (*BatchInsertResult, error) {
+	if err := db.checkWrite(); err != nil {
+		return nil, err
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	coll := db.Collection(collection)
+	o := db.writeOptions(opts)
+
+	insertOpts := options.InsertMany().SetOrdered(ordered)
+	if o.bypassDocumentValidation {
+		insertOpts.SetBypassDocumentValidation(true)
+	}
+	result := &BatchInsertResult{Failed: map[int][]DocumentError{}}
+
+	var err error
+	for start, batch := 0, 0; start < len(docs); start, batch = start+batchSize, batch+1 {
+		end := min(start+batchSize, len(docs))
+
+		var res *mongo.InsertManyResult
+		err1 := withSelection(ctx, db, o.selectionTimeout, func(ctx context.Context) (err error) {
+			res, err = coll.InsertMany(ctx, docs[start:end], insertOpts)
+			return err
+		})
+		if err1 == nil {
+			result.InsertedCount += int64(len(res.InsertedIDs))
+			continue
+		}
+
+		var bwe mongo.BulkWriteException
+		if !errors.As(err1, &bwe) {
+			return result, appendErr(err, fmt.Errorf("batch %d: %w", batch, err1))
+		}
+
+		err = appendErr(err, fmt.Errorf("batch %d: %w", batch, err1))
+
+		// a batch failing only on its write concern still wrote every document
+		if len(bwe.WriteErrors) == 0 {
+			result.InsertedCount += int64(end - start)
+			continue
+		}
+
+		failed := make([]DocumentError, 0, len(bwe.WriteErrors))
+		for _, we := range bwe.WriteErrors {
+			failed = append(failed, DocumentError{Index: start + we.Index, WriteError: we.WriteError})
+		}
+		result.Failed[batch] = failed
+
+		if ordered {
+			result.InsertedCount += int64(failed[0].Index - start)
+			return result, err
+		}
+		result.InsertedCount += int64(end - start - len(failed))
+	}
+	return result, err
+}
+
+func (db *Database) UpdateWithVersion(ctx context.Context, collection string, id any, expectedVersion int64, update bson.M, opts ...WriteOption) error {
+	if err := db.checkWrite(); err != nil {
+		return err
+	}
+
+	versioned := make(bson.M, len(update)+1)
+	for op, fields := range update {
+		versioned[op] = fields
+	}
+
+	inc := bson.M{VersionField: 1}
+	if fields, ok := update["$inc"]; ok {
+		m, ok := fields.(bson.M)
+		if !ok {
+			return fmt.Errorf("update $inc must be a bson.M, got %T", fields)
+		}
+		for field, by := range m {
+			inc[field] = by
+		}
+	}
+	versioned["$inc"] = inc
+
+	filter := bson.D{{Key: "_id", Value: id}, {Key: VersionField, Value: expectedVersion}}
+
+	o := db.writeOptions(opts)
+
+	updateOpts := options.Update()
+	if o.bypassDocumentValidation {
+		updateOpts.SetBypassDocumentValidation(true)
+	}
+
+	var res *mongo.UpdateResult
+	err := withSelection(ctx, db, o.selectionTimeout, func(ctx context.Context) (err error) {
+		res, err = db.Collection(collection).UpdateOne(ctx, filter, versioned, updateOpts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w: `%v` is not at version %d", ErrVersionConflict, id, expectedVersion)
+	}
+	return nil
+}
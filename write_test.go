@@ -0,0 +1,22 @@
+package dbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSelectionTimeoutFailsWritesFast(t *testing.T) {
+	db := lazyDatabase(t)
+
+	start := time.Now()
+	_, err := db.InsertOneWithID(context.Background(), "orders", bson.M{"total": 1}, SelectionTimeout(50*time.Millisecond))
+	if err == nil {
+		t.Fatal("insert into an unreachable server succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("insert failed after %v, want about 50ms", elapsed)
+	}
+}